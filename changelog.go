@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+type changelogCommand struct {
+	From string `long:"from" description:"installed version and build, e.g. 1.20.4-430, defaults to the build of --file" value-name:"VERSION-BUILD"`
+	To   string `long:"to" description:"target version and build, defaults to the build an update would download" value-name:"VERSION-BUILD"`
+}
+
+// parseVersionBuild splits a VERSION-BUILD argument, such as 1.20.4-430, into its version and build number
+func parseVersionBuild(versionBuild string) (string, int, error) {
+	// versions such as 1.21-pre1 can contain dashes too, so the build is after the last one
+	i := strings.LastIndex(versionBuild, "-")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("%w: %s should be a version and build, e.g. 1.20.4-430", errUsage, versionBuild)
+	}
+
+	build, err := strconv.Atoi(versionBuild[i+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %s should be a version and build, e.g. 1.20.4-430", errUsage, versionBuild)
+	}
+
+	return versionBuild[:i], build, nil
+}
+
+// runChangelog prints the changes of every build after --from, or the installed build, up to and including --to
+func runChangelog(ctx context.Context, paperAPIService paperapi.Service, opts *programArgs, out io.Writer) error {
+	var err error
+
+	fromVersion, fromBuild, ok := installedBuild(opts)
+
+	if len(opts.Changelog.From) > 0 {
+		fromVersion, fromBuild, err = parseVersionBuild(opts.Changelog.From)
+		if err != nil {
+			return err
+		}
+	} else if !ok {
+		return fmt.Errorf("%w: couldn't detect the build of %s, use --from to say which build is installed", errUsage, opts.Filename)
+	}
+
+	var target *paperapi.BuildInfo
+
+	if len(opts.Changelog.To) > 0 {
+		toVersion, toBuild, err := parseVersionBuild(opts.Changelog.To)
+		if err != nil {
+			return err
+		}
+
+		target = &paperapi.BuildInfo{Version: toVersion, Build: toBuild}
+	} else {
+		target, err = resolveBuild(ctx, paperAPIService, opts, io.Discard)
+		if err != nil {
+			return err
+		}
+
+		if target == nil {
+			return fmt.Errorf("%w: no builds of %s found", paperapi.ErrBuildNotFound, opts.Project)
+		}
+	}
+
+	builds, err := paperAPIService.GetChangelog(ctx, opts.Project, fromVersion, fromBuild, target.Version, target.Build)
+	if err != nil {
+		return err
+	}
+
+	if opts.Output == "json" {
+		return printJSON(out, builds)
+	}
+
+	if len(builds) == 0 {
+		fmt.Fprintf(out, "No changes between %s-%d and %s-%d.\n", fromVersion, fromBuild, target.Version, target.Build)
+		return nil
+	}
+
+	for _, build := range builds {
+		fmt.Fprintf(out, "%s build #%d", build.Version, build.Build)
+		if !build.Time.IsZero() {
+			fmt.Fprintf(out, " - %s", build.Time.Format("2006-01-02"))
+		}
+
+		if build.Channel != "" && build.Channel != "default" {
+			fmt.Fprint(out, " EXPERIMENTAL")
+		}
+
+		fmt.Fprintln(out)
+
+		for _, change := range build.Changes {
+			fmt.Fprintf(out, "  %s %s\n", shortCommit(change.Commit), change.Summary)
+		}
+	}
+
+	return nil
+}
+
+// shortCommit abbreviates a commit hash the way git does
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+
+	return commit
+}
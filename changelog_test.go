@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+func TestParseVersionBuild(t *testing.T) {
+	tests := []struct {
+		input   string
+		version string
+		build   int
+		valid   bool
+	}{
+		{"1.20.4-430", "1.20.4", 430, true},
+		{"1.21-pre1-12", "1.21-pre1", 12, true},
+		{"3.3.0-SNAPSHOT-390", "3.3.0-SNAPSHOT", 390, true},
+		{"1.20.4", "", 0, false},
+		{"-430", "", 0, false},
+		{"1.20.4-latest", "", 0, false},
+	}
+
+	for _, test := range tests {
+		version, build, err := parseVersionBuild(test.input)
+		if test.valid && (err != nil || version != test.version || build != test.build) {
+			t.Errorf("Expected %s to parse to %s %d but got %s %d %v", test.input, test.version, test.build, version, build, err)
+		}
+
+		if !test.valid && !errors.Is(err, errUsage) {
+			t.Errorf("Expected %s to be a usage error but got %v", test.input, err)
+		}
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	serviceMock := newLatestBuildServiceMock()
+
+	var requested []any
+	serviceMock.getChangelogHandler = func(s *paperServiceMock, ctx context.Context, project string, fromVersion string, fromBuild int, toVersion string, toBuild int) ([]*paperapi.BuildInfo, error) {
+		requested = []any{fromVersion, fromBuild, toVersion, toBuild}
+
+		return []*paperapi.BuildInfo{
+			{
+				Version: "1.20.4",
+				Build:   460,
+				Channel: "default",
+				Time:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+				Changes: []*paperapi.Change{{Commit: "0123456789abcdef", Summary: "Fix something"}},
+			},
+			{
+				Version: "1.20.4",
+				Build:   461,
+				Channel: "default",
+				Time:    time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC),
+				Changes: []*paperapi.Change{{Commit: "fedcba9876543210", Summary: "Fix something else"}},
+			},
+		}, nil
+	}
+
+	opts := &programArgs{Project: "paper", Output: "text"}
+	opts.Changelog.From = "1.20.4-459"
+
+	out := &bytes.Buffer{}
+
+	err := runChangelog(context.Background(), serviceMock, opts, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRequest := []any{"1.20.4", 459, "1.20.4", 461}
+	for i := range expectedRequest {
+		if requested[i] != expectedRequest[i] {
+			t.Errorf("Expected changelog from 1.20.4 #459 to the latest build 1.20.4 #461 but got %v", requested)
+			break
+		}
+	}
+
+	expected := "1.20.4 build #460 - 2024-03-01\n  0123456 Fix something\n1.20.4 build #461 - 2024-03-02\n  fedcba9 Fix something else\n"
+	if out.String() != expected {
+		t.Errorf("Expected output %q but got %q", expected, out.String())
+	}
+}
+
+func TestChangelogFromInstalledBuild(t *testing.T) {
+	serviceMock := newLatestBuildServiceMock()
+
+	var from string
+	serviceMock.getChangelogHandler = func(s *paperServiceMock, ctx context.Context, project string, fromVersion string, fromBuild int, toVersion string, toBuild int) ([]*paperapi.BuildInfo, error) {
+		from = fmt.Sprintf("%s-%d", fromVersion, fromBuild)
+		return []*paperapi.BuildInfo{}, nil
+	}
+
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	opts := &programArgs{Project: "paper", Filename: jar, Output: "text"}
+
+	err := runChangelog(context.Background(), serviceMock, opts, io.Discard)
+	if !errors.Is(err, errUsage) {
+		t.Errorf("Expected a usage error when the installed build can't be detected but got %v", err)
+	}
+
+	writeServerJar(t, jar, "1.20.4-430-abcdef0")
+
+	out := &bytes.Buffer{}
+
+	err = runChangelog(context.Background(), serviceMock, opts, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if from != "1.20.4-430" {
+		t.Errorf("Expected the changelog to start from the installed build 1.20.4-430 but got %s", from)
+	}
+
+	if out.String() != "No changes between 1.20.4-430 and 1.20.4-461.\n" {
+		t.Errorf("Expected no changes from the installed build but got %q", out.String())
+	}
+}
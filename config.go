@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sprpgmr/papermc-fetch/files"
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+	"github.com/sprpgmr/papermc-fetch/webhook"
+	"gopkg.in/yaml.v3"
+)
+
+// config lists the targets to update in a single run
+type config struct {
+	APIURLs  []string       `yaml:"api_urls"`
+	Targets  []targetConfig `yaml:"targets"`
+	Webhooks []webhook.Hook `yaml:"webhooks"`
+}
+
+// targetConfig describes a single server jar to keep up to date
+type targetConfig struct {
+	Name        string `yaml:"name"`
+	Project     string `yaml:"project"`
+	Version     string `yaml:"version"`
+	Build       int    `yaml:"build"`
+	Channel     string `yaml:"channel"`
+	PreReleases string `yaml:"pre_releases"`
+	File        string `yaml:"file"`
+	Artifact    string `yaml:"artifact"`
+	PreHook     string `yaml:"pre_hook"`
+	PostHook    string `yaml:"post_hook"`
+	ArchiveDir  string `yaml:"archive_dir"`
+}
+
+// loadConfig reads and validates the config file at path
+func loadConfig(path string) (*config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	dec := yaml.NewDecoder(file)
+	dec.KnownFields(true)
+
+	cfg := &config{}
+
+	err = dec.Decode(cfg)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: couldn't read config %s: %w", errUsage, path, err)
+	}
+
+	err = cfg.validate()
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid config %s: %w", errUsage, path, err)
+	}
+
+	return cfg, nil
+}
+
+// validate fills in defaults for each target and checks they don't conflict with each other
+func (c *config) validate() error {
+	if len(c.Targets) == 0 {
+		return errors.New("no targets")
+	}
+
+	for i, apiURL := range c.APIURLs {
+		parsed, err := parseAPIURL(apiURL)
+		if err != nil {
+			return err
+		}
+
+		c.APIURLs[i] = parsed
+	}
+
+	files := map[string]bool{}
+	archiveDirs := map[string]bool{}
+
+	for i := range c.Targets {
+		target := &c.Targets[i]
+
+		if len(target.File) == 0 {
+			return fmt.Errorf("target %d has no file", i+1)
+		}
+
+		if files[target.File] {
+			return fmt.Errorf("more than one target downloads to %s", target.File)
+		}
+
+		files[target.File] = true
+
+		if len(target.Name) == 0 {
+			target.Name = target.File
+		}
+
+		if len(target.Project) == 0 {
+			target.Project = paperapi.DefaultProject
+		}
+
+		if len(target.Artifact) == 0 {
+			target.Artifact = paperapi.ApplicationArtifact
+		}
+
+		// the archive is pruned to --keep jars, so a shared one would delete the other targets' jars
+		if len(target.ArchiveDir) == 0 {
+			target.ArchiveDir = target.File + ".archive"
+		}
+
+		if archiveDirs[target.ArchiveDir] {
+			return fmt.Errorf("more than one target archives to %s", target.ArchiveDir)
+		}
+
+		archiveDirs[target.ArchiveDir] = true
+
+		switch target.Channel {
+		case "":
+			target.Channel = "default"
+		case "default", "experimental":
+		default:
+			return fmt.Errorf("target %s has unknown channel %s, expected default or experimental", target.Name, target.Channel)
+		}
+
+		if target.PreReleases != "" && target.PreReleases != "include" && target.PreReleases != "exclude" {
+			return fmt.Errorf("target %s has unknown pre_releases %s, expected include or exclude", target.Name, target.PreReleases)
+		}
+
+		if len(target.Version) > 0 {
+			_, err := paperapi.ParseConstraint(target.Version)
+			if err != nil {
+				return fmt.Errorf("target %s: %w", target.Name, err)
+			}
+		}
+
+		if target.Build != 0 && len(target.Version) == 0 {
+			return fmt.Errorf("target %s has a build but no version", target.Name)
+		}
+	}
+
+	return nil
+}
+
+// apply returns a copy of opts with the target's settings in place of the command line's
+func (t *targetConfig) apply(opts *programArgs) *programArgs {
+	targetOpts := *opts
+
+	targetOpts.Project = t.Project
+	targetOpts.Filename = t.File
+	targetOpts.Artifact = t.Artifact
+	targetOpts.Experimental = t.Channel == "experimental"
+	targetOpts.Prefix = ""
+	targetOpts.Build = t.Build
+	targetOpts.ArchiveDir = t.ArchiveDir
+
+	if len(t.PreReleases) > 0 {
+		targetOpts.PreReleases = t.PreReleases
+	}
+
+	// hooks on the command line apply to every target that doesn't have its own
+	if len(t.PreHook) > 0 {
+		targetOpts.PreHook = t.PreHook
+	}
+
+	if len(t.PostHook) > 0 {
+		targetOpts.PostHook = t.PostHook
+	}
+
+	targetOpts.Version = t.Version
+
+	return &targetOpts
+}
+
+// runConfig updates every target in the config file with the same api service, printing a result for each.
+// The exit code is that of the first target to fail, or otherwise reflects whether any target was updated.
+func runConfig(ctx context.Context, paperAPIService paperapi.Service, archiveService files.ArchiveService, opts *programArgs, out io.Writer, log io.Writer) (int, error) {
+	cfg, err := loadConfig(opts.Config)
+	if err != nil {
+		return exitCodeFor(err), err
+	}
+
+	notifier, err := newNotifier(opts, cfg)
+	if err != nil {
+		return exitCodeFor(err), err
+	}
+
+	results := make([]*updateResult, 0, len(cfg.Targets))
+
+	var firstErr error
+	failed := 0
+
+	for _, target := range cfg.Targets {
+		fmt.Fprintf(log, "Updating %s...\n", target.Name)
+
+		result, err := runTarget(ctx, paperAPIService, archiveService, target.apply(opts), log)
+		result.Target = target.Name
+		results = append(results, result)
+
+		notify(ctx, notifier, result, log)
+
+		if err != nil {
+			fmt.Fprintf(log, "Couldn't update %s: %s\n", target.Name, err)
+
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		fmt.Fprintln(log)
+	}
+
+	if opts.Output == "json" {
+		err = printJSON(out, results)
+		if err != nil {
+			return exitError, err
+		}
+	} else {
+		for _, result := range results {
+			fmt.Fprintf(out, "%s: %s\n", result.Target, result.Action)
+		}
+	}
+
+	if firstErr != nil {
+		return exitCodeFor(firstErr), fmt.Errorf("%d of %d targets failed, first error: %w", failed, len(results), firstErr)
+	}
+
+	return combinedExitCode(results), nil
+}
+
+// combinedExitCode returns exitUpdated if any target was updated, otherwise exitUpdateSkipped if any target has an update available,
+// otherwise exitUpToDate
+func combinedExitCode(results []*updateResult) int {
+	exitCode := exitUpToDate
+
+	for _, result := range results {
+		switch result.ExitCode {
+		case exitUpdated:
+			return exitUpdated
+		case exitUpdateSkipped:
+			exitCode = exitUpdateSkipped
+		}
+	}
+
+	return exitCode
+}
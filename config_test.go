@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "papermc-fetch.yaml")
+
+	err := os.WriteFile(path, []byte(contents), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	path := writeConfig(t, `
+targets:
+  - name: lobby
+    version: 1.20.x
+    file: lobby/paper.jar
+  - project: velocity
+    channel: experimental
+    version: 3.3.0-SNAPSHOT
+    build: 300
+    file: proxy/velocity.jar
+`)
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lobby := cfg.Targets[0]
+	if lobby.Project != paperapi.DefaultProject || lobby.Channel != "default" || lobby.Artifact != paperapi.ApplicationArtifact {
+		t.Errorf("Expected defaults to be filled in but got %+v", lobby)
+	}
+
+	lobbyOpts := lobby.apply(&programArgs{Prefix: "1.19", Retries: 5})
+	if lobbyOpts.Prefix != "" || lobbyOpts.Version != "1.20.x" || lobbyOpts.Filename != "lobby/paper.jar" || lobbyOpts.Retries != 5 {
+		t.Errorf("Expected the version constraint to replace the prefix but got %+v", lobbyOpts)
+	}
+
+	if archiveDir(lobbyOpts) != "lobby/paper.jar.archive" {
+		t.Errorf("Expected each target to archive next to its own file but got %s", archiveDir(lobbyOpts))
+	}
+
+	proxy := cfg.Targets[1]
+	if proxy.Name != "proxy/velocity.jar" {
+		t.Errorf("Expected the name to default to the file but got %s", proxy.Name)
+	}
+
+	proxyOpts := proxy.apply(&programArgs{})
+	if proxyOpts.Version != "3.3.0-SNAPSHOT" || proxyOpts.Build != 300 || proxyOpts.Prefix != "" || !proxyOpts.Experimental {
+		t.Errorf("Expected a pinned experimental build but got %+v", proxyOpts)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	configs := map[string]string{
+		"no targets":     "targets: []",
+		"missing file":   "targets:\n  - project: paper",
+		"duplicate file": "targets:\n  - file: paper.jar\n  - file: paper.jar",
+		"bad channel":    "targets:\n  - file: paper.jar\n    channel: beta",
+		"build only":     "targets:\n  - file: paper.jar\n    build: 100",
+		"bad version":    "targets:\n  - file: paper.jar\n    version: \">=\"",
+		"unknown field":  "targets:\n  - file: paper.jar\n    prefix: \"1.20\"",
+		"bad api url":    "api_urls: [\"api.papermc.io\"]\ntargets:\n  - file: paper.jar",
+		"shared archive": "targets:\n  - file: a/paper.jar\n    archive_dir: /backups\n  - file: b/paper.jar\n    archive_dir: /backups",
+	}
+
+	for name, contents := range configs {
+		_, err := loadConfig(writeConfig(t, contents))
+		if !errors.Is(err, errUsage) {
+			t.Errorf("%s: expected a usage error but got %v", name, err)
+		}
+	}
+}
+
+func TestConfigRejectsArchiveDir(t *testing.T) {
+	path := writeConfig(t, "targets:\n  - file: a/paper.jar\n  - file: b/velocity.jar\n    project: velocity")
+
+	exitCode, err := runMainProgram(mockServiceFactory(newLatestBuildServiceMock()), &archiveServiceMock{}, []string{"--config", path, "--archive-dir", "/backups"})
+	if !errors.Is(err, errUsage) || exitCode != exitUsage {
+		t.Errorf("Expected a usage error for an archive shared by every target but got %d %v", exitCode, err)
+	}
+}
+
+func TestConfigRunsEveryTarget(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, `
+targets:
+  - name: survival
+    version: ">=1.20 <1.21"
+    file: `+filepath.Join(dir, "survival.jar")+`
+  - name: proxy
+    project: velocity
+    file: `+filepath.Join(dir, "velocity.jar")+`
+`)
+
+	serviceMock := newLatestBuildServiceMock()
+
+	projects := []string{}
+	constraints := []string{}
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		projects = append(projects, project)
+		constraint := ""
+		if filter.Constraint != nil {
+			constraint = filter.Constraint.String()
+		}
+
+		constraints = append(constraints, constraint)
+
+		return newLatestBuildServiceMock().GetLatestBuild(ctx, project, unstable, filter)
+	}
+
+	serviceMock.downloadExistsHandler = func(s *paperServiceMock, filePath string, buildInfo *paperapi.BuildInfo, artifact string) (bool, error) {
+		return buildInfo.ProjectID == "velocity", nil
+	}
+
+	services := 0
+	factory := func(options paperapi.Options) paperapi.Service {
+		services++
+		return serviceMock
+	}
+
+	exitCode, err := runMainProgram(factory, &archiveServiceMock{}, []string{"--config", path, "--keep", "0"})
+	if err != nil || exitCode != exitUpdated {
+		t.Errorf("Expected exit code %d when a target was updated but got %d %v", exitUpdated, exitCode, err)
+	}
+
+	if services != 1 {
+		t.Errorf("Expected every target to share one service but %d were created", services)
+	}
+
+	if len(projects) != 2 || projects[0] != "paper" || projects[1] != "velocity" || constraints[0] != ">=1.20 <1.21" || constraints[1] != "" {
+		t.Errorf("Expected each target's project and version to be used but got %v %v", projects, constraints)
+	}
+}
+
+func TestConfigAPIURLs(t *testing.T) {
+	path := writeConfig(t, `
+api_urls:
+  - https://mirror.example.com/v2/
+  - https://api.papermc.io/v2
+targets:
+  - file: paper.jar
+`)
+
+	args := []string{"--config", path, "--skip-download", "--keep", "0"}
+
+	var apiOptions paperapi.Options
+	factory := func(options paperapi.Options) paperapi.Service {
+		apiOptions = options
+		return &paperServiceMock{}
+	}
+
+	runMainProgram(factory, &archiveServiceMock{}, args)
+
+	if strings.Join(apiOptions.APIURLs, " ") != "https://mirror.example.com/v2 https://api.papermc.io/v2" {
+		t.Errorf("Expected the config's api urls to be used in order but got %v", apiOptions.APIURLs)
+	}
+
+	runMainProgram(factory, &archiveServiceMock{}, append(args, "--api-url", "http://localhost:8080/v2"))
+
+	if strings.Join(apiOptions.APIURLs, " ") != "http://localhost:8080/v2" {
+		t.Errorf("Expected --api-url to replace the config's api urls but got %v", apiOptions.APIURLs)
+	}
+}
+
+func TestConfigFailureSetsExitCode(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, `
+targets:
+  - name: first
+    file: `+filepath.Join(dir, "first.jar")+`
+  - name: second
+    file: `+filepath.Join(dir, "second.jar")+`
+`)
+
+	serviceMock := newLatestBuildServiceMock()
+
+	downloaded := []string{}
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filePath string) error {
+		if filepath.Base(filePath) == "first.jar" {
+			return paperapi.ErrChecksumMismatch
+		}
+
+		downloaded = append(downloaded, filepath.Base(filePath))
+		return nil
+	}
+
+	exitCode, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--config", path, "--keep", "0"})
+	if !errors.Is(err, paperapi.ErrChecksumMismatch) || exitCode != exitChecksumMismatch {
+		t.Errorf("Expected exit code %d for the failed target but got %d %v", exitChecksumMismatch, exitCode, err)
+	}
+
+	if len(downloaded) != 1 || downloaded[0] != "second.jar" {
+		t.Error("Expected the second target to still be downloaded")
+	}
+}
+
+func TestCombinedExitCode(t *testing.T) {
+	tests := []struct {
+		exitCodes []int
+		expected  int
+	}{
+		{[]int{exitUpToDate, exitUpToDate}, exitUpToDate},
+		{[]int{exitUpToDate, exitUpdateSkipped}, exitUpdateSkipped},
+		{[]int{exitUpdateSkipped, exitUpdated, exitUpToDate}, exitUpdated},
+	}
+
+	for _, test := range tests {
+		results := []*updateResult{}
+		for _, exitCode := range test.exitCodes {
+			results = append(results, &updateResult{ExitCode: exitCode})
+		}
+
+		actual := combinedExitCode(results)
+		if actual != test.expected {
+			t.Errorf("Expected %d for %v but got %d", test.expected, test.exitCodes, actual)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package files
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrNotArchived is returned when restoring a file that isn't in the archive
+var ErrNotArchived = errors.New("file not found in archive")
+
+// ArchivedFile is a copy of a file kept in an archive directory
+type ArchivedFile struct {
+	Name     string
+	Path     string
+	Archived time.Time
+}
+
+// ArchiveService keeps copies of previously downloaded files in a directory so they can be restored later
+type ArchiveService interface {
+	Add(dir string, filePath string, name string) error
+	List(dir string) ([]ArchivedFile, error)
+	Restore(dir string, name string, filePath string) error
+	Prune(dir string, keep int) ([]ArchivedFile, error)
+}
+
+// GetArchiveService returns the default archive service
+func GetArchiveService() ArchiveService {
+	return &archiveServiceImpl{}
+}
+
+type archiveServiceImpl struct{}
+
+// Add copies filePath into dir as name, replacing any file already archived with that name
+func (s *archiveServiceImpl) Add(dir string, filePath string, name string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	archivePath := filepath.Join(dir, name)
+
+	err = copyFileAtomic(filePath, archivePath)
+	if err != nil {
+		return err
+	}
+
+	// files are ordered by when they were archived, not when they were downloaded
+	now := time.Now()
+	return os.Chtimes(archivePath, now, now)
+}
+
+// List returns the files archived in dir, most recently archived first
+func (s *archiveServiceImpl) List(dir string) ([]ArchivedFile, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []ArchivedFile{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	archived := make([]ArchivedFile, 0, len(entries))
+
+	for _, entry := range entries {
+		// skip directories and any copies left behind part way through
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		archived = append(archived, ArchivedFile{
+			Name:     entry.Name(),
+			Path:     filepath.Join(dir, entry.Name()),
+			Archived: info.ModTime(),
+		})
+	}
+
+	slices.SortFunc[[]ArchivedFile](archived, func(a, b ArchivedFile) int {
+		return b.Archived.Compare(a.Archived)
+	})
+
+	return archived, nil
+}
+
+// Restore atomically replaces filePath with the file archived in dir as name, and marks it as the most recently archived
+func (s *archiveServiceImpl) Restore(dir string, name string, filePath string) error {
+	archivePath := filepath.Join(dir, name)
+
+	_, err := os.Stat(archivePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotArchived
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = copyFileAtomic(archivePath, filePath)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return os.Chtimes(archivePath, now, now)
+}
+
+// Prune removes all but the keep most recently archived files in dir, returning the files it removed
+func (s *archiveServiceImpl) Prune(dir string, keep int) ([]ArchivedFile, error) {
+	archived, err := s.List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(archived) <= keep {
+		return []ArchivedFile{}, nil
+	}
+
+	pruned := archived[max(keep, 0):]
+	for _, file := range pruned {
+		err = os.Remove(file.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pruned, nil
+}
+
+// copyFileAtomic copies src into a temporary file next to dst, and renames it over dst once it has been synced
+func copyFileAtomic(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+
+	// does nothing once the temp file has been renamed over dst
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return err
+	}
+
+	err = out.Chmod(0644)
+	if err != nil {
+		return err
+	}
+
+	err = out.Sync()
+	if err != nil {
+		return err
+	}
+
+	err = out.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(out.Name(), dst)
+}
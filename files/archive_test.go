@@ -0,0 +1,113 @@
+package files
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveAddListAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	archiveDir := filepath.Join(dir, "archive")
+	jar := filepath.Join(dir, "paper.jar")
+
+	archiveService := GetArchiveService()
+
+	archived, err := archiveService.List(archiveDir)
+	if err != nil || len(archived) != 0 {
+		t.Errorf("Expected a missing archive to be empty but got %v %v", archived, err)
+	}
+
+	names := []string{"1.20.2-318.jar", "1.20.4-430.jar", "1.20.4-461.jar"}
+	for i, name := range names {
+		err = os.WriteFile(jar, []byte(name), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = archiveService.Add(archiveDir, jar, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// make the order deterministic regardless of the file system's timestamp resolution
+		archivedAt := time.Now().Add(time.Duration(i-len(names)) * time.Minute)
+		os.Chtimes(filepath.Join(archiveDir, name), archivedAt, archivedAt)
+	}
+
+	archived, err = archiveService.List(archiveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(archived) != 3 || archived[0].Name != "1.20.4-461.jar" || archived[2].Name != "1.20.2-318.jar" {
+		t.Errorf("Expected archived files newest first but got %v", archived)
+	}
+
+	pruned, err := archiveService.Prune(archiveDir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pruned) != 1 || pruned[0].Name != "1.20.2-318.jar" {
+		t.Errorf("Expected the oldest file to be pruned but got %v", pruned)
+	}
+
+	archived, err = archiveService.List(archiveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(archived) != 2 {
+		t.Errorf("Expected 2 files to be kept but there were %d", len(archived))
+	}
+}
+
+func TestArchiveRestore(t *testing.T) {
+	dir := t.TempDir()
+	archiveDir := filepath.Join(dir, "archive")
+	jar := filepath.Join(dir, "paper.jar")
+
+	archiveService := GetArchiveService()
+
+	err := os.WriteFile(jar, []byte("old build"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = archiveService.Add(archiveDir, jar, "1.20.4-430.jar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(jar, []byte("new build"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = archiveService.Restore(archiveDir, "1.20.4-430.jar", jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := os.ReadFile(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(contents) != "old build" {
+		t.Errorf("Expected the archived file to be restored but got %q", contents)
+	}
+
+	err = archiveService.Restore(archiveDir, "1.20.4-1.jar", jar)
+	if !errors.Is(err, ErrNotArchived) {
+		t.Errorf("Expected ErrNotArchived restoring a missing file but got %v", err)
+	}
+
+	contents, err = os.ReadFile(jar)
+	if err != nil || string(contents) != "old build" {
+		t.Errorf("Expected a failed restore to leave the file untouched but got %q %v", contents, err)
+	}
+}
@@ -2,6 +2,9 @@ module github.com/sprpgmr/papermc-fetch
 
 go 1.21.5
 
-require github.com/jessevdk/go-flags v1.5.0
+require (
+	github.com/jessevdk/go-flags v1.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require golang.org/x/sys v0.0.0-20210320140829-1e4c9ba3b0c4 // indirect
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+// errHookFailed is wrapped by errors caused by a pre or post hook exiting unsuccessfully
+var errHookFailed = errors.New("hook failed")
+
+// runHook runs command with the shell, passing the update's details in PAPER_* environment variables.
+// The command's output is written to log.
+func runHook(ctx context.Context, name string, command string, opts *programArgs, result *updateResult, buildInfo *paperapi.BuildInfo, log io.Writer) error {
+	fmt.Fprintf(log, "Running %s...\n", name)
+
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	cmd := exec.CommandContext(ctx, shell, flag, command)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	cmd.Env = append(os.Environ(), hookEnv(opts, result, buildInfo)...)
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", errHookFailed, name, err)
+	}
+
+	return nil
+}
+
+// hookEnv returns the environment variables describing the update to hooks
+func hookEnv(opts *programArgs, result *updateResult, buildInfo *paperapi.BuildInfo) []string {
+	oldBuild := ""
+	if result.OldBuild != 0 {
+		oldBuild = strconv.Itoa(result.OldBuild)
+	}
+
+	return []string{
+		"PAPER_PROJECT=" + opts.Project,
+		"PAPER_JAR=" + opts.Filename,
+		"PAPER_OLD_VERSION=" + result.OldVersion,
+		"PAPER_OLD_BUILD=" + oldBuild,
+		"PAPER_NEW_VERSION=" + buildInfo.Version,
+		"PAPER_BUILD=" + strconv.Itoa(buildInfo.Build),
+		"PAPER_CHANNEL=" + buildInfo.Channel,
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+	"github.com/sprpgmr/papermc-fetch/webhook"
+)
+
+func TestHooksReceiveUpdateDetails(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "env")
+	jar := filepath.Join(dir, "paper.jar")
+
+	writeServerJar(t, jar, "1.20.4-430-abcdef0")
+
+	serviceMock := newLatestBuildServiceMock()
+	archiveService := &archiveServiceMock{}
+
+	args := []string{"--file", jar, "--pre-hook", `echo "pre $PAPER_OLD_VERSION-$PAPER_OLD_BUILD $PAPER_NEW_VERSION-$PAPER_BUILD $PAPER_JAR" >> ` + envFile, "--post-hook", `echo "post $PAPER_PROJECT $PAPER_CHANNEL" >> ` + envFile}
+
+	exitCode, err := runMainProgram(mockServiceFactory(serviceMock), archiveService, args)
+	if err != nil || exitCode != exitUpdated {
+		t.Fatalf("Expected the update to succeed but got %d %v", exitCode, err)
+	}
+
+	env, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "pre 1.20.4-430 1.20.4-461 " + jar + "\npost paper default\n"
+	if string(env) != expected {
+		t.Errorf("Expected hooks to output %q but got %q", expected, env)
+	}
+}
+
+func TestFailingPreHookAbortsUpdate(t *testing.T) {
+	serviceMock := newLatestBuildServiceMock()
+
+	exitCode, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--pre-hook", "exit 3"})
+	if exitCode != exitHookFailed || err == nil {
+		t.Errorf("Expected exit code %d but got %d %v", exitHookFailed, exitCode, err)
+	}
+
+	if serviceMock.ranDownload {
+		t.Error("Shouldn't have downloaded after the pre-hook failed")
+	}
+}
+
+func TestHooksDontRunWithoutDownload(t *testing.T) {
+	serviceMock := newLatestBuildServiceMock()
+
+	exitCode, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--skip-download", "--pre-hook", "exit 1", "--post-hook", "exit 1"})
+	if err != nil || exitCode != exitUpdateSkipped {
+		t.Errorf("Expected hooks not to run when skipping the download but got %d %v", exitCode, err)
+	}
+}
+
+func TestPostHookDoesntRunAfterFailedDownload(t *testing.T) {
+	serviceMock := newLatestBuildServiceMock()
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filePath string) error {
+		return paperapi.ErrChecksumMismatch
+	}
+
+	exitCode, _ := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--post-hook", "exit 1"})
+	if exitCode != exitChecksumMismatch {
+		t.Errorf("Expected exit code %d from the download rather than the post-hook but got %d", exitChecksumMismatch, exitCode)
+	}
+}
+
+func TestTargetHooksOverrideFlags(t *testing.T) {
+	opts := &programArgs{PreHook: "backup.sh", PostHook: "restart.sh"}
+
+	target := targetConfig{PostHook: "systemctl restart lobby"}
+	targetOpts := target.apply(opts)
+
+	if targetOpts.PreHook != "backup.sh" || !strings.Contains(targetOpts.PostHook, "lobby") {
+		t.Errorf("Expected the target's post-hook to replace the flag's but got %q and %q", targetOpts.PreHook, targetOpts.PostHook)
+	}
+}
+
+func TestFailingPostHookKeepsDownload(t *testing.T) {
+	events := []webhook.Event{}
+	ts := newWebhookReceiver(t, &events)
+
+	result, err := runTarget(context.Background(), newLatestBuildServiceMock(), &archiveServiceMock{}, &programArgs{
+		Project:  "paper",
+		Filename: filepath.Join(t.TempDir(), "paper.jar"),
+		Artifact: paperapi.ApplicationArtifact,
+		PostHook: "exit 1",
+	}, io.Discard)
+
+	if result.ExitCode != exitHookFailed || err == nil {
+		t.Errorf("Expected exit code %d but got %d %v", exitHookFailed, result.ExitCode, err)
+	}
+
+	if result.Action != actionDownloaded || len(result.HookError) == 0 || len(result.Error) > 0 {
+		t.Errorf("Expected the download to be reported with the hook error but got %+v", result)
+	}
+
+	notifier, err := newNotifier(&programArgs{Webhooks: []string{ts.URL}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notify(context.Background(), notifier, result, io.Discard)
+
+	if len(events) != 1 || events[0].Type != webhook.EventUpdated || len(events[0].HookError) == 0 {
+		t.Errorf("Expected an updated event with the hook error but received %+v", events)
+	}
+}
@@ -0,0 +1,155 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Options configures how requests reach the internet
+type Options struct {
+	// Proxy is the url of an http, https or socks5 proxy, which may include a username and password.
+	// If it's empty, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used instead.
+	Proxy string
+
+	// CACerts are pem files of certificates to trust on top of the system's, e.g. for a proxy that inspects tls
+	CACerts []string
+
+	// ClientCert and ClientKey are pem files of the certificate presented to servers that ask for one.
+	// ClientKey may be empty if the key is in ClientCert.
+	ClientCert string
+	ClientKey  string
+
+	// ConnectTimeout limits how long connecting, including the tls handshake, can take, 0 for no limit
+	ConnectTimeout time.Duration
+
+	// ResponseTimeout limits how long to wait for a server to start responding after sending a request, 0 for no limit.
+	// It doesn't limit how long reading the response takes, so large downloads aren't cut off.
+	ResponseTimeout time.Duration
+}
+
+// GetClient returns an http.Client configured by options, to be shared by every request so connections are reused
+func GetClient(options Options) (*http.Client, error) {
+	proxy, err := proxyFunc(options.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := tlsConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   options.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   options.ConnectTimeout,
+		ResponseHeaderTimeout: options.ResponseTimeout,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// proxyFunc returns the proxy function for the proxy url provided, or one reading the environment if it's empty
+func proxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	if len(proxy) == 0 {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %s: %w", proxy, err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy %s, expected an http, https or socks5 url such as socks5://proxy.example.com:1080", proxy)
+	}
+
+	if len(proxyURL.Host) == 0 {
+		return nil, fmt.Errorf("invalid proxy %s, it has no host", proxy)
+	}
+
+	return http.ProxyURL(proxyURL), nil
+}
+
+// tlsConfig returns the tls config for the certificates in options, or nil to use the defaults if there are none
+func tlsConfig(options Options) (*tls.Config, error) {
+	if len(options.CACerts) == 0 && len(options.ClientCert) == 0 && len(options.ClientKey) == 0 {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(options.CACerts) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			// the extra certificates are still worth trusting on systems without a readable pool
+			pool = x509.NewCertPool()
+		}
+
+		for _, path := range options.CACerts {
+			pem, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't read ca certificates: %w", err)
+			}
+
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no pem certificates found in %s", path)
+			}
+		}
+
+		config.RootCAs = pool
+	}
+
+	if len(options.ClientKey) > 0 && len(options.ClientCert) == 0 {
+		return nil, errors.New("a client key needs a client certificate")
+	}
+
+	if len(options.ClientCert) > 0 {
+		keyPath := options.ClientKey
+		if len(keyPath) == 0 {
+			keyPath = options.ClientCert
+		}
+
+		cert, err := tls.LoadX509KeyPair(options.ClientCert, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// Sleep waits for d, returning early with ctx's error if ctx is done first, so waiting to retry can be cancelled
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
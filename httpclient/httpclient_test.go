@@ -0,0 +1,448 @@
+package httpclient
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePEM writes blocks to a new pem file named name, returning its path
+func writePEM(t *testing.T, name string, blocks ...*pem.Block) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	for _, block := range blocks {
+		err = pem.Encode(file, block)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return path
+}
+
+// serverCAFile writes the certificate of the tls server ts to a pem file, so clients can trust it
+func serverCAFile(t *testing.T, ts *httptest.Server) string {
+	return writePEM(t, "ca.pem", &pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+}
+
+// newCertificate creates a certificate for commonName signed by parent, or self-signed if parent is nil
+func newCertificate(t *testing.T, commonName string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent = template
+		parentKey = key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+func get(t *testing.T, options Options, url string) (string, error) {
+	t.Helper()
+
+	client, err := GetClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	return string(body), err
+}
+
+func TestCACerts(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+
+	defer ts.Close()
+
+	_, err := get(t, Options{}, ts.URL)
+	if err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("Expected an untrusted certificate error without the ca but got %v", err)
+	}
+
+	options := Options{}
+	options.CACerts = []string{serverCAFile(t, ts)}
+
+	body, err := get(t, options, ts.URL)
+	if err != nil || body != "ok" {
+		t.Errorf("Expected the server to be trusted with its ca but got %q %v", body, err)
+	}
+}
+
+func TestClientCert(t *testing.T) {
+	ca, caKey := newCertificate(t, "test ca", nil, nil)
+	cert, key := newCertificate(t, "papermc-fetch", ca, caKey)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	ts.StartTLS()
+
+	defer ts.Close()
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certBlock := &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}
+	keyBlock := &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}
+
+	options := Options{}
+	options.CACerts = []string{serverCAFile(t, ts)}
+
+	_, err = get(t, options, ts.URL)
+	if err == nil {
+		t.Error("Expected the server to reject a client without a certificate")
+	}
+
+	options.ClientCert = writePEM(t, "client.pem", certBlock)
+	options.ClientKey = writePEM(t, "client.key", keyBlock)
+
+	body, err := get(t, options, ts.URL)
+	if err != nil || body != "papermc-fetch" {
+		t.Errorf("Expected the client certificate to be presented but got %q %v", body, err)
+	}
+
+	options.ClientCert = writePEM(t, "client.pem", certBlock, keyBlock)
+	options.ClientKey = ""
+
+	body, err = get(t, options, ts.URL)
+	if err != nil || body != "papermc-fetch" {
+		t.Errorf("Expected the key to be read from the certificate file but got %q %v", body, err)
+	}
+}
+
+// tunnel copies between the two connections until either closes
+func tunnel(a net.Conn, b net.Conn) {
+	go func() {
+		io.Copy(a, b)
+		a.Close()
+	}()
+
+	io.Copy(b, a)
+	b.Close()
+}
+
+func TestHTTPProxy(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+
+	defer ts.Close()
+
+	connects := []string{}
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// "user:password", base64 encoded
+		if r.Header.Get("Proxy-Authorization") != "Basic dXNlcjpwYXNzd29yZA==" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+
+		connects = append(connects, r.Host)
+
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			target.Close()
+			return
+		}
+
+		if buf.Reader.Buffered() > 0 {
+			io.CopyN(target, buf, int64(buf.Reader.Buffered()))
+		}
+
+		tunnel(conn, target)
+	}))
+
+	defer proxy.Close()
+
+	options := Options{}
+	options.CACerts = []string{serverCAFile(t, ts)}
+	options.Proxy = "http://user:wrong@" + proxy.Listener.Addr().String()
+
+	_, err := get(t, options, ts.URL)
+	if err == nil {
+		t.Error("Expected the proxy to reject the wrong password")
+	}
+
+	options.Proxy = "http://user:password@" + proxy.Listener.Addr().String()
+
+	body, err := get(t, options, ts.URL)
+	if err != nil || body != "ok" {
+		t.Errorf("Expected the request to be tunnelled through the proxy but got %q %v", body, err)
+	}
+
+	if len(connects) != 1 || connects[0] != ts.Listener.Addr().String() {
+		t.Errorf("Expected a single CONNECT to %s but got %v", ts.Listener.Addr(), connects)
+	}
+}
+
+// serveSOCKS5 accepts connections on listener, speaking just enough socks5 to connect clients authenticated
+// with username and password to the address they ask for. The addresses connected to are sent to connects.
+func serveSOCKS5(listener net.Listener, username string, password string, connects chan<- string) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			r := bufio.NewReader(conn)
+
+			// greeting: version, number of methods, methods
+			header := make([]byte, 2)
+			if _, err := io.ReadFull(r, header); err != nil {
+				conn.Close()
+				return
+			}
+
+			if _, err := io.CopyN(io.Discard, r, int64(header[1])); err != nil {
+				conn.Close()
+				return
+			}
+
+			conn.Write([]byte{5, 2})
+
+			// username and password: version, username length, username, password length, password
+			auth := make([]byte, 2)
+			io.ReadFull(r, auth)
+			user := make([]byte, auth[1])
+			io.ReadFull(r, user)
+			io.ReadFull(r, auth[:1])
+			pass := make([]byte, auth[0])
+			io.ReadFull(r, pass)
+
+			if string(user) != username || string(pass) != password {
+				conn.Write([]byte{1, 1})
+				conn.Close()
+				return
+			}
+
+			conn.Write([]byte{1, 0})
+
+			// request: version, command, reserved, address type, address, port
+			request := make([]byte, 4)
+			io.ReadFull(r, request)
+
+			var host string
+
+			switch request[3] {
+			case 1:
+				ip := make([]byte, 4)
+				io.ReadFull(r, ip)
+				host = net.IP(ip).String()
+			case 3:
+				length, _ := r.ReadByte()
+				name := make([]byte, length)
+				io.ReadFull(r, name)
+				host = string(name)
+			case 4:
+				ip := make([]byte, 16)
+				io.ReadFull(r, ip)
+				host = net.IP(ip).String()
+			}
+
+			port := make([]byte, 2)
+			io.ReadFull(r, port)
+
+			addr := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+			connects <- addr
+
+			target, err := net.Dial("tcp", addr)
+			if err != nil {
+				conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+				conn.Close()
+				return
+			}
+
+			conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+			tunnel(&bufferedConn{Conn: conn, r: r}, target)
+		}()
+	}
+}
+
+// bufferedConn reads from r, which may have buffered bytes already read from Conn
+type bufferedConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+func TestSOCKS5Proxy(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+
+	defer ts.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	connects := make(chan string, 10)
+	go serveSOCKS5(listener, "user", "password", connects)
+
+	options := Options{}
+	options.CACerts = []string{serverCAFile(t, ts)}
+	options.Proxy = "socks5://user:password@" + listener.Addr().String()
+
+	body, err := get(t, options, ts.URL)
+	if err != nil || body != "ok" {
+		t.Fatalf("Expected the request to be sent through the socks5 proxy but got %q %v", body, err)
+	}
+
+	if addr := <-connects; addr != ts.Listener.Addr().String() {
+		t.Errorf("Expected the proxy to connect to %s but it connected to %s", ts.Listener.Addr(), addr)
+	}
+}
+
+func TestResponseTimeout(t *testing.T) {
+	done := make(chan struct{})
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+
+	defer ts.Close()
+	defer close(done)
+
+	options := Options{}
+	options.CACerts = []string{serverCAFile(t, ts)}
+	options.ResponseTimeout = 50 * time.Millisecond
+
+	_, err := get(t, options, ts.URL)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Expected a timeout waiting for a response but got %v", err)
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	noPEM := filepath.Join(t.TempDir(), "empty.pem")
+
+	err := os.WriteFile(noPEM, []byte("not a certificate"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]Options{
+		"ftp proxy":           {Proxy: "ftp://proxy.example.com"},
+		"proxy without host":  {Proxy: "http://"},
+		"missing ca file":     {CACerts: []string{filepath.Join(t.TempDir(), "missing.pem")}},
+		"ca file without pem": {CACerts: []string{noPEM}},
+		"key without cert":    {ClientKey: noPEM},
+		"invalid cert":        {ClientCert: noPEM},
+	}
+
+	for name, options := range tests {
+		_, err := GetClient(options)
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSleep(t *testing.T) {
+	err := Sleep(context.Background(), time.Millisecond)
+	if err != nil {
+		t.Errorf("Expected to wait without an error but got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+
+	err = Sleep(ctx, time.Minute)
+	if !errors.Is(err, context.Canceled) || time.Since(start) > time.Second {
+		t.Errorf("Expected a cancelled context to end the wait straight away but got %v after %v", err, time.Since(start))
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sprpgmr/papermc-fetch/jarinfo"
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+type identifyCommand struct{}
+
+// knownProjects are the ids of the papermc projects, which jars name in their manifest's Implementation-Title
+var knownProjects = []string{"paper", "folia", "velocity", "waterfall", "travertine"}
+
+// jarProject returns the project id a jar's manifest title names, or ok false if it isn't a known papermc project,
+// e.g. CraftBukkit
+func jarProject(title string) (string, bool) {
+	project := strings.ToLower(strings.TrimSpace(title))
+
+	return project, slices.Contains(knownProjects, project)
+}
+
+// identifyResult is the result of the identify command when the output is json
+type identifyResult struct {
+	File     string              `json:"file"`
+	Official bool                `json:"official"`
+	Build    *paperapi.BuildInfo `json:"build,omitempty"`
+}
+
+// runIdentify searches the builds of --project for the one whose download is --file, and records it in the lock file if found.
+// The versions searched are narrowed down by --version and --prefix, or otherwise by the version the jar says it is.
+func runIdentify(ctx context.Context, paperAPIService paperapi.Service, opts *programArgs, out io.Writer) error {
+	_, err := os.Stat(opts.Filename)
+	if err != nil {
+		return err
+	}
+
+	project := opts.Project
+
+	filter, err := opts.versionFilter()
+	if err != nil {
+		return err
+	}
+
+	info, err := jarinfo.Read(opts.Filename)
+	if err == nil {
+		// the jar only decides the project when --project wasn't given, and only if it names one the api knows
+		if jarProject, ok := jarProject(info.Project); ok && !opts.projectSet {
+			project = jarProject
+		}
+
+		// the jar could have been modified, but it can't be an official build of a different version
+		if filter.Constraint == nil && len(filter.Prefix) == 0 && len(info.MinecraftVersion) > 0 {
+			constraint, err := paperapi.ParseConstraint("=" + info.MinecraftVersion)
+			if err == nil {
+				filter.Constraint = constraint
+			}
+		}
+	}
+
+	buildInfo, err := paperAPIService.IdentifyJar(ctx, project, opts.Filename, filter)
+	if errors.Is(err, paperapi.ErrBuildNotFound) {
+		if opts.Output == "json" {
+			printJSON(out, &identifyResult{File: opts.Filename})
+		}
+
+		msg := fmt.Sprintf("%s is not an official build of %s", opts.Filename, project)
+		if filter.Constraint != nil {
+			msg += " " + filter.Constraint.String()
+		}
+
+		return fmt.Errorf("%w: %s", paperapi.ErrBuildNotFound, msg)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the jar may have been found from its hash alone, so record it to save doing this again
+	installOpts := *opts
+	installOpts.Artifact = paperapi.ApplicationArtifact
+	recordInstall(paperAPIService, &installOpts, buildInfo, "", time.Time{}, io.Discard)
+
+	if opts.Output == "json" {
+		return printJSON(out, &identifyResult{File: opts.Filename, Official: true, Build: buildInfo})
+	}
+
+	fmt.Fprintf(out, "%s is %s %s build #%d (%s)\n", opts.Filename, buildInfo.ProjectID, buildInfo.Version, buildInfo.Build, buildInfo.Channel)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+func TestIdentifyNarrowsByJarMetadata(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	writeServerJar(t, jar, "1.20.2-318-abcdef0")
+
+	serviceMock := &paperServiceMock{}
+	serviceMock.identifyJarHandler = func(s *paperServiceMock, ctx context.Context, project string, filePath string, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		if project != "paper" || filter.Constraint == nil || !filter.Constraint.Matches("1.20.2") || filter.Constraint.Matches("1.20.4") {
+			t.Errorf("Expected only paper 1.20.2 to be searched but got %s %v", project, filter.Constraint)
+		}
+
+		return &paperapi.BuildInfo{
+			ProjectID: project,
+			Version:   "1.20.2",
+			Build:     318,
+			Channel:   "default",
+			Downloads: paperapi.DownloadInfo{paperapi.ApplicationArtifact: {Name: "paper-1.20.2-318.jar", Sha256: "asdf"}},
+		}, nil
+	}
+
+	out := &bytes.Buffer{}
+
+	err := runIdentify(context.Background(), serviceMock, &programArgs{Project: "paper", Filename: jar}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "paper 1.20.2 build #318") {
+		t.Errorf("Expected the build to be reported but got %s", out)
+	}
+
+	lock, err := readLock(jar)
+	if err != nil || lock == nil || lock.Build != 318 {
+		t.Errorf("Expected the identified build to be recorded in the lock file but got %+v %v", lock, err)
+	}
+}
+
+func TestIdentifyUnofficialJar(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+
+	err := os.WriteFile(jar, []byte("not paper"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serviceMock := &paperServiceMock{}
+	serviceMock.identifyJarHandler = func(s *paperServiceMock, ctx context.Context, project string, filePath string, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		if filter.Constraint != nil {
+			t.Errorf("Expected every version to be searched for a jar without metadata but got %v", filter.Constraint)
+		}
+
+		return nil, paperapi.ErrBuildNotFound
+	}
+
+	exitCode, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--file", jar, "identify"})
+	if exitCode != exitNotFound || err == nil || !strings.Contains(err.Error(), "not an official build") {
+		t.Errorf("Expected exit code %d for an unofficial build but got %d %v", exitNotFound, exitCode, err)
+	}
+
+	_, err = os.Stat(lockFilePath(jar))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected no lock file for an unofficial build but got %v", err)
+	}
+}
+
+func TestIdentifyProjectFromJar(t *testing.T) {
+	tests := []struct {
+		title    string
+		args     []string
+		expected string
+	}{
+		{"Folia", []string{}, "folia"},
+		{"Folia", []string{"--project", "paper"}, "paper"},
+		{"CraftBukkit", []string{}, "paper"},
+		{"CraftBukkit", []string{"--project", "velocity"}, "velocity"},
+	}
+
+	for _, test := range tests {
+		jar := filepath.Join(t.TempDir(), "server.jar")
+		writeManifestJar(t, jar, test.title, "1.20.2-318-abcdef0")
+
+		searched := ""
+		serviceMock := &paperServiceMock{}
+		serviceMock.identifyJarHandler = func(s *paperServiceMock, ctx context.Context, project string, filePath string, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+			searched = project
+			return nil, paperapi.ErrBuildNotFound
+		}
+
+		args := append([]string{"--file", jar}, test.args...)
+		runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, append(args, "identify"))
+
+		if searched != test.expected {
+			t.Errorf("%s %v: expected %s to be searched but searched %q", test.title, test.args, test.expected, searched)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sprpgmr/papermc-fetch/jarinfo"
+)
+
+type infoCommand struct{}
+
+// jarInfo is the result of the info command when the output is json
+type jarInfo struct {
+	File string `json:"file"`
+	*jarinfo.Info
+	Lock *lockFile `json:"lock,omitempty"`
+}
+
+// runInfo prints the version and build detected from the contents of --file, and when it was installed if its lock
+// file still describes it
+func runInfo(opts *programArgs, out io.Writer) error {
+	lock, err := readLock(opts.Filename)
+	if err != nil {
+		return err
+	}
+
+	info, err := jarinfo.Read(opts.Filename)
+	if err != nil && lock == nil {
+		return err
+	}
+
+	// artifacts other than the server jar can't be inspected, but the lock file still says what they are
+	if err != nil {
+		info = &jarinfo.Info{MinecraftVersion: lock.Version, Build: lock.Build}
+	}
+
+	if opts.Output == "json" {
+		return printJSON(out, &jarInfo{File: opts.Filename, Info: info, Lock: lock})
+	}
+
+	fmt.Fprintf(out, "File:      %s\n", opts.Filename)
+
+	if len(info.Project) > 0 {
+		fmt.Fprintf(out, "Project:   %s\n", info.Project)
+	}
+
+	fmt.Fprintf(out, "Version:   %s\n", info.MinecraftVersion)
+
+	if info.Build != 0 {
+		fmt.Fprintf(out, "Build:     %d\n", info.Build)
+	} else {
+		fmt.Fprintln(out, "Build:     unknown")
+	}
+
+	if lock != nil {
+		fmt.Fprintf(out, "Installed: %s\n", lock.InstalledAt.Local().Format(time.DateTime))
+		fmt.Fprintf(out, "From:      %s\n", lock.URL)
+		fmt.Fprintf(out, "Sha256:    %s\n", lock.Sha256)
+	}
+
+	return nil
+}
+
+// installedBuild returns the version and build of the jar currently installed, from its lock file or otherwise detected
+// from the jar itself. ok is false if neither of them know.
+func installedBuild(opts *programArgs) (version string, build int, ok bool) {
+	lock, err := readLock(opts.Filename)
+	if err == nil && lock != nil {
+		return lock.Version, lock.Build, true
+	}
+
+	info, err := jarinfo.Read(opts.Filename)
+	if err == nil && len(info.MinecraftVersion) > 0 && info.Build != 0 {
+		return info.MinecraftVersion, info.Build, true
+	}
+
+	return "", 0, false
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sprpgmr/papermc-fetch/jarinfo"
+)
+
+// writeServerJar writes a jar whose manifest says it's the version and build provided
+func writeServerJar(t *testing.T, jar string, implementationVersion string) {
+	writeManifestJar(t, jar, "Paper", implementationVersion)
+}
+
+func writeManifestJar(t *testing.T, jar string, implementationTitle string, implementationVersion string) {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+
+	f, err := w.Create("META-INF/MANIFEST.MF")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.Write([]byte("Manifest-Version: 1.0\nImplementation-Title: " + implementationTitle + "\nImplementation-Version: " + implementationVersion + "\n"))
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(jar, buf.Bytes(), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunInfo(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	writeServerJar(t, jar, "1.20.2-318-abcdef0")
+
+	out := &bytes.Buffer{}
+
+	err := runInfo(&programArgs{Filename: jar, Output: "text"}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "Version:   1.20.2\n") || !strings.Contains(out.String(), "Build:     318\n") {
+		t.Errorf("Expected the version and build to be printed but got %s", out)
+	}
+
+	_, err = runMainProgram(mockServiceFactory(&paperServiceMock{}), &archiveServiceMock{}, []string{"--file", filepath.Join(t.TempDir(), "missing.jar"), "info"})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a not exist error for a missing jar but got %v", err)
+	}
+}
+
+func TestInfoExitCodeForUnknownJar(t *testing.T) {
+	if exitCodeFor(jarinfo.ErrUnknownJar) != exitNotFound {
+		t.Errorf("Expected exit code %d for a jar without a version", exitNotFound)
+	}
+}
+
+func TestInstalledBuildFromJar(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	opts := &programArgs{Filename: jar}
+
+	_, _, ok := installedBuild(opts)
+	if ok {
+		t.Error("Expected the installed build to be unknown without a jar")
+	}
+
+	writeServerJar(t, jar, "1.20.2-318-abcdef0")
+
+	version, build, ok := installedBuild(opts)
+	if !ok || version != "1.20.2" || build != 318 {
+		t.Errorf("Expected the jar's own version to be used but got %s %d %t", version, build, ok)
+	}
+}
+
+func TestUpgradeMessage(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	writeServerJar(t, jar, "1.20.2-318-abcdef0")
+
+	opts := &programArgs{Project: "paper", Filename: jar, Artifact: "application"}
+	log := &bytes.Buffer{}
+
+	_, err := runUpdate(context.Background(), newLatestBuildServiceMock(), &archiveServiceMock{}, opts, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(log.String(), "Upgrading 1.20.2#318 → 1.20.4#461\n") {
+		t.Errorf("Expected the upgrade to be described but got %s", log)
+	}
+}
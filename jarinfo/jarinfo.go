@@ -0,0 +1,255 @@
+package jarinfo
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownJar is returned when a jar doesn't contain anything identifying the version it's for
+var ErrUnknownJar = errors.New("couldn't detect the version of the jar")
+
+// Info describes the server a jar contains
+type Info struct {
+	// Project is the name of the server software, e.g. Paper or Velocity
+	Project string `json:"project,omitempty"`
+
+	// MinecraftVersion is the version of Minecraft, or of the project for proxies such as Velocity
+	MinecraftVersion string `json:"minecraft_version,omitempty"`
+
+	// Build is the papermc build number, or 0 if it couldn't be detected
+	Build int `json:"build,omitempty"`
+
+	// ImplementationVersion is the raw Implementation-Version from the jar's manifest
+	ImplementationVersion string `json:"implementation_version,omitempty"`
+}
+
+var (
+	// e.g. 1.20.4-461-4fbfe6a (Paper since 1.20) or 3.3.0-SNAPSHOT (git-8e5b5b2c-b300) (Velocity)
+	buildVersionPattern = regexp.MustCompile(`^([^\s]+?)-(\d+)-[0-9a-f]+$`)
+	velocityPattern     = regexp.MustCompile(`^([^\s]+) \(git-[0-9a-f]+-b(\d+)\)$`)
+
+	// e.g. git-Paper-318 (MC: 1.20.2) (older Paper and Waterfall)
+	gitVersionPattern = regexp.MustCompile(`^git-([A-Za-z]+)-(\d+) \(MC: ([^)]+)\)$`)
+)
+
+// Read opens the jar at filePath and detects the version it's for, from its version.json and META-INF/MANIFEST.MF.
+// Paperclip jars keep the server in a jar listed in META-INF/versions.list, which is read too.
+func Read(filePath string) (*Info, error) {
+	r, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	info := &Info{}
+
+	err = readJar(&r.Reader, info)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(info.MinecraftVersion) == 0 && info.Build == 0 {
+		return nil, fmt.Errorf("%w %s", ErrUnknownJar, filePath)
+	}
+
+	return info, nil
+}
+
+// readJar fills in anything info is missing from the jar, then from any jars it bundles
+func readJar(r *zip.Reader, info *Info) error {
+	err := readVersionJSON(r, info)
+	if err != nil {
+		return err
+	}
+
+	err = readManifest(r, info)
+	if err != nil {
+		return err
+	}
+
+	bundled, err := readVersionsList(r)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range bundled {
+		b, err := readFile(r, path.Join("META-INF/versions", name))
+		if err != nil {
+			return err
+		}
+
+		nested, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return fmt.Errorf("couldn't open bundled jar %s: %w", name, err)
+		}
+
+		err = readJar(nested, info)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readFile returns the contents of name in r, or nil if it doesn't exist
+func readFile(r *zip.Reader, name string) ([]byte, error) {
+	f, err := r.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// readVersionJSON reads the Minecraft version from the version.json Mojang includes in server jars
+func readVersionJSON(r *zip.Reader, info *Info) error {
+	b, err := readFile(r, "version.json")
+	if err != nil || b == nil {
+		return err
+	}
+
+	version := struct {
+		ID string `json:"id"`
+	}{}
+
+	err = json.Unmarshal(b, &version)
+	if err != nil {
+		return fmt.Errorf("invalid version.json: %w", err)
+	}
+
+	if len(info.MinecraftVersion) == 0 {
+		info.MinecraftVersion = version.ID
+	}
+
+	return nil
+}
+
+// readManifest reads the project, version and build from the Implementation-Title and Implementation-Version manifest attributes
+func readManifest(r *zip.Reader, info *Info) error {
+	b, err := readFile(r, "META-INF/MANIFEST.MF")
+	if err != nil || b == nil {
+		return err
+	}
+
+	attributes := parseManifest(b)
+
+	if len(info.Project) == 0 {
+		info.Project = attributes["Implementation-Title"]
+	}
+
+	implementationVersion := attributes["Implementation-Version"]
+	if len(implementationVersion) == 0 {
+		return nil
+	}
+
+	if len(info.ImplementationVersion) == 0 {
+		info.ImplementationVersion = implementationVersion
+	}
+
+	version, build, project := parseImplementationVersion(implementationVersion)
+
+	if len(info.MinecraftVersion) == 0 {
+		info.MinecraftVersion = version
+	}
+
+	if info.Build == 0 {
+		info.Build = build
+	}
+
+	if len(info.Project) == 0 {
+		info.Project = project
+	}
+
+	return nil
+}
+
+// parseManifest returns the main attributes of a manifest, joining continuation lines
+func parseManifest(b []byte) map[string]string {
+	attributes := map[string]string{}
+	last := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		// a blank line ends the main section
+		if len(line) == 0 {
+			break
+		}
+
+		if strings.HasPrefix(line, " ") && len(last) > 0 {
+			attributes[last] += line[1:]
+			continue
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		last = strings.TrimSpace(name)
+		attributes[last] = strings.TrimSpace(value)
+	}
+
+	return attributes
+}
+
+// parseImplementationVersion extracts the version, build and project from the forms of Implementation-Version papermc uses
+func parseImplementationVersion(implementationVersion string) (string, int, string) {
+	if match := gitVersionPattern.FindStringSubmatch(implementationVersion); match != nil {
+		build, _ := strconv.Atoi(match[2])
+		return match[3], build, match[1]
+	}
+
+	if match := velocityPattern.FindStringSubmatch(implementationVersion); match != nil {
+		build, _ := strconv.Atoi(match[2])
+		return match[1], build, ""
+	}
+
+	if match := buildVersionPattern.FindStringSubmatch(implementationVersion); match != nil {
+		build, _ := strconv.Atoi(match[2])
+		return match[1], build, ""
+	}
+
+	return "", 0, ""
+}
+
+// readVersionsList returns the paths, relative to META-INF/versions, of the jars a Paperclip jar bundles.
+// Each line of versions.list is a sha256 hash, an id and a path separated by tabs.
+func readVersionsList(r *zip.Reader) ([]string, error) {
+	b, err := readFile(r, "META-INF/versions.list")
+	if err != nil || b == nil {
+		return nil, err
+	}
+
+	paths := []string{}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) != 3 {
+			continue
+		}
+
+		paths = append(paths, fields[2])
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,146 @@
+package jarinfo
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildJar returns the bytes of a zip containing files
+func buildJar(t *testing.T, files map[string][]byte) []byte {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+
+	for name, contents := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = f.Write(contents)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func writeJar(t *testing.T, files map[string][]byte) string {
+	jar := filepath.Join(t.TempDir(), "server.jar")
+
+	err := os.WriteFile(jar, buildJar(t, files), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return jar
+}
+
+func TestReadPaperclipJar(t *testing.T) {
+	server := buildJar(t, map[string][]byte{
+		"version.json":         []byte(`{"id": "1.20.4", "name": "1.20.4", "world_version": 3700}`),
+		"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\r\nImplementation-Title: Paper\r\nImplementation-Version: 1.20.4-461-4fbfe6a\r\n\r\nName: net/minecraft/\r\nImplementation-Version: other\r\n"),
+	})
+
+	jar := writeJar(t, map[string][]byte{
+		"META-INF/MANIFEST.MF":                []byte("Manifest-Version: 1.0\nMain-Class: io.papermc.paperclip.Main\n"),
+		"META-INF/versions.list":              []byte("abcdef\tpaper-1.20.4\tpaper-1.20.4.jar\n"),
+		"META-INF/versions/paper-1.20.4.jar":  server,
+		"META-INF/libraries/some-library.jar": []byte("not a jar"),
+		"io/papermc/paperclip/Main.class":     []byte{0xca, 0xfe},
+	})
+
+	info, err := Read(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Info{Project: "Paper", MinecraftVersion: "1.20.4", Build: 461, ImplementationVersion: "1.20.4-461-4fbfe6a"}
+	if *info != expected {
+		t.Errorf("Expected %+v but got %+v", expected, *info)
+	}
+}
+
+func TestReadLegacyManifest(t *testing.T) {
+	jar := writeJar(t, map[string][]byte{
+		"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\nImplementation-Version: git-Paper-318 (MC: 1.2\n 0.2)\n"),
+	})
+
+	info, err := Read(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Project != "Paper" || info.MinecraftVersion != "1.20.2" || info.Build != 318 {
+		t.Errorf("Expected Paper 1.20.2 build 318 but got %+v", *info)
+	}
+}
+
+func TestReadVelocityJar(t *testing.T) {
+	jar := writeJar(t, map[string][]byte{
+		"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\nImplementation-Title: Velocity\nImplementation-Version: 3.3.0-SNAPSHOT (git-8e5b5b2c-b300)\n"),
+	})
+
+	info, err := Read(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Project != "Velocity" || info.MinecraftVersion != "3.3.0-SNAPSHOT" || info.Build != 300 {
+		t.Errorf("Expected Velocity 3.3.0-SNAPSHOT build 300 but got %+v", *info)
+	}
+}
+
+func TestReadVanillaJar(t *testing.T) {
+	jar := writeJar(t, map[string][]byte{
+		"version.json": []byte(`{"id": "1.20.4"}`),
+	})
+
+	info, err := Read(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.MinecraftVersion != "1.20.4" || info.Build != 0 {
+		t.Errorf("Expected 1.20.4 without a build but got %+v", *info)
+	}
+}
+
+func TestReadUnknownJar(t *testing.T) {
+	jar := writeJar(t, map[string][]byte{
+		"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\n"),
+	})
+
+	_, err := Read(jar)
+	if !errors.Is(err, ErrUnknownJar) {
+		t.Errorf("Expected ErrUnknownJar but got %v", err)
+	}
+}
+
+func TestReadNotAJar(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+
+	err := os.WriteFile(jar, []byte("not a zip"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Read(jar)
+	if err == nil {
+		t.Error("Expected an error reading a file that isn't a jar")
+	}
+
+	_, err = Read(filepath.Join(t.TempDir(), "missing.jar"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a not exist error but got %v", err)
+	}
+}
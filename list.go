@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+type listCommand struct {
+	Versions listVersionsCommand `command:"versions" description:"list the versions of --project, optionally filtered by --prefix"`
+	Builds   listBuildsCommand   `command:"builds" description:"list the builds of a version with their channel and sha256"`
+}
+
+type listVersionsCommand struct{}
+
+type listBuildsCommand struct {
+	Args struct {
+		Version string `positional-arg-name:"version"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// runList prints the versions or builds asked for by the list subcommand to out
+func runList(ctx context.Context, paperAPIService paperapi.Service, opts *programArgs, subcommand string, out io.Writer) error {
+	switch subcommand {
+	case "versions":
+		filter, err := opts.versionFilter()
+		if err != nil {
+			return err
+		}
+
+		versions, err := paperAPIService.GetVersionsList(ctx, opts.Project, filter)
+		if err != nil {
+			return err
+		}
+
+		return printVersions(out, opts.Output, versions)
+	case "builds":
+		builds, err := paperAPIService.GetBuilds(ctx, opts.Project, opts.List.Builds.Args.Version)
+		if err != nil {
+			return err
+		}
+
+		return printBuilds(out, opts.Output, builds)
+	}
+
+	return fmt.Errorf("%w: unknown list command %s", errUsage, subcommand)
+}
+
+// printVersions prints versions to out, oldest first, as a table or json
+func printVersions(out io.Writer, format string, versions *paperapi.VersionsList) error {
+	if format == "json" {
+		return printJSON(out, versions)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION")
+
+	for _, version := range versions.Versions {
+		fmt.Fprintln(w, version)
+	}
+
+	return w.Flush()
+}
+
+// printBuilds prints the builds of a version to out, oldest first, as a table or json
+func printBuilds(out io.Writer, format string, builds *paperapi.VersionBuilds) error {
+	if format == "json" {
+		return printJSON(out, builds)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BUILD\tCHANNEL\tSHA256")
+
+	for _, build := range builds.Builds {
+		sha256 := ""
+		if build.Downloads.Application() != nil {
+			sha256 = build.Downloads.Application().Sha256
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\n", build.Build, build.Channel, sha256)
+	}
+
+	return w.Flush()
+}
+
+// printJSON prints v to out as indented json
+func printJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+func newListServiceMock() *paperServiceMock {
+	serviceMock := &paperServiceMock{}
+
+	serviceMock.getVersionsListHandler = func(s *paperServiceMock, ctx context.Context, project string, filter paperapi.VersionFilter) (*paperapi.VersionsList, error) {
+		return &paperapi.VersionsList{
+			ProjectID: project,
+			Versions:  []string{"1.20.2", "1.20.4"},
+		}, nil
+	}
+
+	serviceMock.getBuildsHandler = func(s *paperServiceMock, ctx context.Context, project string, version string) (*paperapi.VersionBuilds, error) {
+		return &paperapi.VersionBuilds{
+			ProjectID: project,
+			Version:   version,
+			Builds: []*paperapi.BuildInfo{
+				{
+					Build:   3,
+					Channel: "experimental",
+					Downloads: paperapi.DownloadInfo{
+						paperapi.ApplicationArtifact: {Name: "paper-1.20.4-3.jar", Sha256: "abcd"},
+					},
+				},
+				{
+					Build:   461,
+					Channel: "default",
+					Downloads: paperapi.DownloadInfo{
+						paperapi.ApplicationArtifact: {Name: "paper-1.20.4-461.jar", Sha256: "efgh"},
+					},
+				},
+			},
+		}, nil
+	}
+
+	return serviceMock
+}
+
+func TestListVersionsTable(t *testing.T) {
+	serviceMock := newListServiceMock()
+
+	requestedPrefix := ""
+	listVersions := serviceMock.getVersionsListHandler
+	serviceMock.getVersionsListHandler = func(s *paperServiceMock, ctx context.Context, project string, filter paperapi.VersionFilter) (*paperapi.VersionsList, error) {
+		requestedPrefix = filter.Prefix
+		return listVersions(s, ctx, project, filter)
+	}
+
+	opts := &programArgs{Project: "paper", Prefix: "1.20", Output: "text"}
+	out := &bytes.Buffer{}
+
+	err := runList(context.Background(), serviceMock, opts, "versions", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requestedPrefix != "1.20" {
+		t.Errorf("Expected versions to be filtered by 1.20 but prefix was %q", requestedPrefix)
+	}
+
+	expected := "VERSION\n1.20.2\n1.20.4\n"
+	if out.String() != expected {
+		t.Errorf("Expected output %q but got %q", expected, out.String())
+	}
+}
+
+func TestListBuildsTable(t *testing.T) {
+	opts := &programArgs{Project: "paper", Output: "text"}
+	opts.List.Builds.Args.Version = "1.20.4"
+
+	out := &bytes.Buffer{}
+
+	err := runList(context.Background(), newListServiceMock(), opts, "builds", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "BUILD  CHANNEL       SHA256\n3      experimental  abcd\n461    default       efgh\n"
+	if out.String() != expected {
+		t.Errorf("Expected output %q but got %q", expected, out.String())
+	}
+}
+
+func TestListBuildsJSON(t *testing.T) {
+	opts := &programArgs{Project: "paper", Output: "json"}
+	opts.List.Builds.Args.Version = "1.20.4"
+
+	out := &bytes.Buffer{}
+
+	err := runList(context.Background(), newListServiceMock(), opts, "builds", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builds := &paperapi.VersionBuilds{}
+
+	err = json.Unmarshal(out.Bytes(), builds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if builds.Version != "1.20.4" || len(builds.Builds) != 2 || builds.Builds[1].Downloads.Application().Sha256 != "efgh" {
+		t.Errorf("Expected the builds of 1.20.4 to be printed as json but got %s", out.String())
+	}
+}
+
+func TestListCommandParses(t *testing.T) {
+	serviceMock := newListServiceMock()
+
+	requestedVersion := ""
+	listBuilds := serviceMock.getBuildsHandler
+	serviceMock.getBuildsHandler = func(s *paperServiceMock, ctx context.Context, project string, version string) (*paperapi.VersionBuilds, error) {
+		requestedVersion = version
+		return listBuilds(s, ctx, project, version)
+	}
+
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"list", "builds", "1.20.4", "--output", "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requestedVersion != "1.20.4" {
+		t.Errorf("Expected builds of 1.20.4 to be listed but got %q", requestedVersion)
+	}
+
+	if serviceMock.ranDownload {
+		t.Error("Didn't expect list to download anything")
+	}
+}
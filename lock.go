@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+// lockFile records what was installed to a file, so later runs know which build it is without hashing it
+type lockFile struct {
+	Project     string    `json:"project"`
+	Version     string    `json:"version"`
+	Build       int       `json:"build"`
+	Artifact    string    `json:"artifact"`
+	Sha256      string    `json:"sha256"`
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"mod_time"`
+	InstalledAt time.Time `json:"installed_at"`
+	URL         string    `json:"url"`
+}
+
+// lockFilePath returns the path of the lock file recording what was installed to filePath
+func lockFilePath(filePath string) string {
+	return filePath + ".lock.json"
+}
+
+// readLock returns the lock file for filePath if it still describes the file, going by its size and modification time.
+// It returns nil if there's no lock file or the file has changed since it was written.
+func readLock(filePath string) (*lockFile, error) {
+	b, err := os.ReadFile(lockFilePath(filePath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &lockFile{}
+
+	err = json.Unmarshal(b, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if stat.Size() != lock.Size || !stat.ModTime().Equal(lock.ModTime) {
+		return nil, nil
+	}
+
+	return lock, nil
+}
+
+// matches returns true if the lock says the file is the artifact of buildInfo
+func (l *lockFile) matches(buildInfo *paperapi.BuildInfo, artifact string, sha256 string) bool {
+	return l.Project == buildInfo.ProjectID && l.Version == buildInfo.Version && l.Build == buildInfo.Build && l.Artifact == artifact && l.Sha256 == sha256
+}
+
+// recordInstall writes the lock file for the artifact of buildInfo that was installed to --file from url at installedAt.
+// If url is empty, the file wasn't just downloaded and the url it would be downloaded from is recorded.
+// The install has already succeeded, so failing to write the lock file is only logged and nil is returned.
+func recordInstall(paperAPIService paperapi.Service, opts *programArgs, buildInfo *paperapi.BuildInfo, url string, installedAt time.Time, log io.Writer) *lockFile {
+	var err error
+	if len(url) == 0 {
+		url, err = paperAPIService.DownloadURL(buildInfo, opts.Artifact)
+	}
+
+	if err == nil {
+		var lock *lockFile
+
+		lock, err = writeLock(opts.Filename, buildInfo, opts.Artifact, url, installedAt)
+		if err == nil {
+			return lock
+		}
+	}
+
+	fmt.Fprintln(log, "Couldn't write lock file: ", err)
+
+	return nil
+}
+
+// writeLock records that filePath is the artifact of buildInfo, downloaded from url at installedAt.
+// If installedAt is zero, the file's modification time is used instead.
+func writeLock(filePath string, buildInfo *paperapi.BuildInfo, artifact string, url string, installedAt time.Time) (*lockFile, error) {
+	download, err := buildInfo.Downloads.Artifact(artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &lockFile{
+		Project:     buildInfo.ProjectID,
+		Version:     buildInfo.Version,
+		Build:       buildInfo.Build,
+		Artifact:    artifact,
+		Sha256:      download.Sha256,
+		Size:        stat.Size(),
+		ModTime:     stat.ModTime(),
+		InstalledAt: installedAt,
+		URL:         url,
+	}
+
+	if installedAt.IsZero() {
+		lock.InstalledAt = stat.ModTime()
+	}
+
+	b, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	// written to a temporary file first, so a crash never leaves a half written lock file
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(lockFilePath(filePath))+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(append(b, '\n'))
+	if err != nil {
+		tmp.Close()
+		return nil, err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return lock, os.Rename(tmp.Name(), lockFilePath(filePath))
+}
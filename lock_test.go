@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sprpgmr/papermc-fetch/files"
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+func TestLockFileDescribesFile(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+
+	err := os.WriteFile(jar, []byte("paper"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buildInfo, _ := newLatestBuildServiceMock().GetLatestBuild(context.Background(), "paper", false, paperapi.VersionFilter{})
+	installedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err = writeLock(jar, buildInfo, paperapi.ApplicationArtifact, "https://example.com/paper.jar", installedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := readLock(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lock == nil || !lock.matches(buildInfo, paperapi.ApplicationArtifact, "asdf") || !lock.InstalledAt.Equal(installedAt) || lock.URL != "https://example.com/paper.jar" {
+		t.Errorf("Expected the lock file to describe the build but got %+v", lock)
+	}
+
+	err = os.WriteFile(jar, []byte("replaced by hand"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err = readLock(jar)
+	if err != nil || lock != nil {
+		t.Errorf("Expected no lock for a file that's changed but got %+v %v", lock, err)
+	}
+
+	lock, err = readLock(filepath.Join(t.TempDir(), "missing.jar"))
+	if err != nil || lock != nil {
+		t.Errorf("Expected no lock for a file without one but got %+v %v", lock, err)
+	}
+}
+
+func TestLockFileWrittenAfterDownload(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+
+	serviceMock := newLatestBuildServiceMock()
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filePath string) error {
+		return os.WriteFile(filePath, []byte("paper"), 0644)
+	}
+
+	opts := &programArgs{Project: "paper", Filename: jar, Artifact: paperapi.ApplicationArtifact}
+
+	result, err := runUpdate(context.Background(), serviceMock, &archiveServiceMock{}, opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Installed == nil || result.Installed.Build != 461 || !strings.HasSuffix(result.Installed.URL, "/builds/461/downloads/paper-1.20.4-461.jar") {
+		t.Errorf("Expected the install to be recorded in the result but got %+v", result.Installed)
+	}
+
+	// the next run shouldn't need to hash the jar
+	serviceMock.downloadExistsHandler = func(s *paperServiceMock, filePath string, buildInfo *paperapi.BuildInfo, artifact string) (bool, error) {
+		t.Error("Didn't expect to hash a jar the lock file describes")
+		return false, nil
+	}
+
+	result, err = runUpdate(context.Background(), serviceMock, &archiveServiceMock{}, opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Action != actionUpToDate || result.Installed == nil {
+		t.Errorf("Expected the lock file to show the jar is up to date but got %s %+v", result.Action, result.Installed)
+	}
+}
+
+func TestLockFileRecordsMirror(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+
+	serviceMock := newLatestBuildServiceMock()
+	serviceMock.downloadedFrom = "https://mirror.example.com/v2/projects/paper/versions/1.20.4/builds/461/downloads/paper-1.20.4-461.jar"
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filePath string) error {
+		return os.WriteFile(filePath, []byte("paper"), 0644)
+	}
+
+	opts := &programArgs{Project: "paper", Filename: jar, Artifact: paperapi.ApplicationArtifact}
+
+	_, err := runUpdate(context.Background(), serviceMock, &archiveServiceMock{}, opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := readLock(jar)
+	if err != nil || lock == nil || lock.URL != serviceMock.downloadedFrom {
+		t.Errorf("Expected the lock file to record the mirror that served the jar but got %+v %v", lock, err)
+	}
+}
+
+func TestLockFileWrittenForVerifiedJar(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+
+	err := os.WriteFile(jar, []byte("paper"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serviceMock := newLatestBuildServiceMock()
+	serviceMock.downloadExistsHandler = func(s *paperServiceMock, filePath string, buildInfo *paperapi.BuildInfo, artifact string) (bool, error) {
+		return true, nil
+	}
+
+	opts := &programArgs{Project: "paper", Filename: jar, Artifact: paperapi.ApplicationArtifact}
+
+	_, err = runUpdate(context.Background(), serviceMock, &archiveServiceMock{}, opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := readLock(jar)
+	if err != nil || lock == nil {
+		t.Fatalf("Expected a lock file after verifying the jar but got %v", err)
+	}
+
+	stat, _ := os.Stat(jar)
+	if !lock.InstalledAt.Equal(stat.ModTime()) {
+		t.Errorf("Expected the install time of a jar that wasn't downloaded to be its modification time but got %s", lock.InstalledAt)
+	}
+}
+
+func TestRollbackRemovesLockFile(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	opts := &programArgs{Filename: jar}
+
+	archiveService := files.GetArchiveService()
+	setupArchive(t, archiveService, jar, archiveDir(opts), "1.20.4-430.jar", "1.20.4-461.jar")
+
+	buildInfo, _ := newLatestBuildServiceMock().GetLatestBuild(context.Background(), "paper", false, paperapi.VersionFilter{})
+
+	_, err := writeLock(jar, buildInfo, paperapi.ApplicationArtifact, "https://example.com/paper.jar", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = runRollback(archiveService, opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = os.Stat(lockFilePath(jar))
+	if !os.IsNotExist(err) {
+		t.Errorf("Expected the lock file to be removed after rolling back but got %v", err)
+	}
+}
+
+func TestInfoShowsLockFile(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	writeServerJar(t, jar, "1.20.4-461-abcdef0")
+
+	buildInfo, _ := newLatestBuildServiceMock().GetLatestBuild(context.Background(), "paper", false, paperapi.VersionFilter{})
+
+	_, err := writeLock(jar, buildInfo, paperapi.ApplicationArtifact, "https://example.com/paper.jar", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+
+	err = runInfo(&programArgs{Filename: jar, Output: "text"}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "From:      https://example.com/paper.jar\n") {
+		t.Errorf("Expected where the jar came from to be printed but got %s", out)
+	}
+}
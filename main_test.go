@@ -1,28 +1,76 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/jessevdk/go-flags"
+	"github.com/sprpgmr/papermc-fetch/files"
 	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
 )
 
 type paperServiceMock struct {
-	getLatestBuildHandler  func(s *paperServiceMock, unstable bool, versionPrefix string) (*paperapi.BuildInfo, error)
+	getLatestBuildHandler  func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error)
+	getBuildHandler        func(s *paperServiceMock, ctx context.Context, project string, version string, build int) (*paperapi.BuildInfo, error)
+	getVersionsListHandler func(s *paperServiceMock, ctx context.Context, project string, filter paperapi.VersionFilter) (*paperapi.VersionsList, error)
+	getBuildsHandler       func(s *paperServiceMock, ctx context.Context, project string, version string) (*paperapi.VersionBuilds, error)
+	identifyJarHandler     func(s *paperServiceMock, ctx context.Context, project string, filePath string, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error)
+	getChangelogHandler    func(s *paperServiceMock, ctx context.Context, project string, fromVersion string, fromBuild int, toVersion string, toBuild int) ([]*paperapi.BuildInfo, error)
 	isValidDownloadHandler func(s *paperServiceMock, filePath string, hash string) (bool, error)
-	downloadJarHandler     func(s *paperServiceMock, buildInfo *paperapi.BuildInfo, filepath string) error
-	downloadExistsHandler  func(s *paperServiceMock, filepath string, buildInfo *paperapi.BuildInfo) (bool, error)
+	downloadedFrom         string
+	downloadJarHandler     func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filepath string) error
+	downloadExistsHandler  func(s *paperServiceMock, filepath string, buildInfo *paperapi.BuildInfo, artifact string) (bool, error)
 	ranDownload            bool
 }
 
-func (s *paperServiceMock) GetLatestBuild(unstable bool, versionPrefix string) (*paperapi.BuildInfo, error) {
+func (s *paperServiceMock) GetLatestBuild(ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
 	if s.getLatestBuildHandler != nil {
-		return s.getLatestBuildHandler(s, unstable, versionPrefix)
+		return s.getLatestBuildHandler(s, ctx, project, unstable, filter)
 	}
 
 	return nil, nil
 }
 
+func (s *paperServiceMock) GetBuild(ctx context.Context, project string, version string, build int) (*paperapi.BuildInfo, error) {
+	if s.getBuildHandler != nil {
+		return s.getBuildHandler(s, ctx, project, version, build)
+	}
+
+	return nil, nil
+}
+
+func (s *paperServiceMock) GetVersionsList(ctx context.Context, project string, filter paperapi.VersionFilter) (*paperapi.VersionsList, error) {
+	if s.getVersionsListHandler != nil {
+		return s.getVersionsListHandler(s, ctx, project, filter)
+	}
+
+	return &paperapi.VersionsList{}, nil
+}
+
+func (s *paperServiceMock) GetBuilds(ctx context.Context, project string, version string) (*paperapi.VersionBuilds, error) {
+	if s.getBuildsHandler != nil {
+		return s.getBuildsHandler(s, ctx, project, version)
+	}
+
+	return &paperapi.VersionBuilds{}, nil
+}
+
+func (s *paperServiceMock) GetChangelog(ctx context.Context, project string, fromVersion string, fromBuild int, toVersion string, toBuild int) ([]*paperapi.BuildInfo, error) {
+	if s.getChangelogHandler != nil {
+		return s.getChangelogHandler(s, ctx, project, fromVersion, fromBuild, toVersion, toBuild)
+	}
+
+	return []*paperapi.BuildInfo{}, nil
+}
+
 func (s *paperServiceMock) IsValidDownload(filePath string, hash string) (bool, error) {
 	if s.isValidDownloadHandler != nil {
 		return s.isValidDownloadHandler(s, filePath, hash)
@@ -31,56 +79,87 @@ func (s *paperServiceMock) IsValidDownload(filePath string, hash string) (bool,
 	return true, nil
 }
 
-func (s *paperServiceMock) DownloadJar(buildInfo *paperapi.BuildInfo, filepath string) error {
+func (s *paperServiceMock) DownloadJar(ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filepath string) (string, error) {
 	if s.downloadJarHandler != nil {
-		return s.downloadJarHandler(s, buildInfo, filepath)
+		err := s.downloadJarHandler(s, ctx, buildInfo, artifact, filepath)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		s.ranDownload = true
 	}
 
-	s.ranDownload = true
+	if len(s.downloadedFrom) > 0 {
+		return s.downloadedFrom, nil
+	}
 
-	return nil
+	return s.DownloadURL(buildInfo, artifact)
 }
 
-func (s *paperServiceMock) DownloadExists(filepath string, buildInfo *paperapi.BuildInfo) (bool, error) {
-	if s.downloadExistsHandler != nil {
-		return s.downloadExistsHandler(s, filepath, buildInfo)
+func (s *paperServiceMock) IdentifyJar(ctx context.Context, project string, filePath string, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+	if s.identifyJarHandler != nil {
+		return s.identifyJarHandler(s, ctx, project, filePath, filter)
 	}
 
-	return false, nil
+	return nil, paperapi.ErrBuildNotFound
 }
 
-type fileServiceMock struct {
-	fileExistsHandler     func(s *fileServiceMock, filepath string) bool
-	deleteIfExistsHandler func(s *fileServiceMock, filepath string) error
-	deleteIfExistsCalled  int
+func (s *paperServiceMock) DownloadURL(buildInfo *paperapi.BuildInfo, artifact string) (string, error) {
+	download, err := buildInfo.Downloads.Artifact(artifact)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("https://api.papermc.io/v2/projects/%s/versions/%s/builds/%d/downloads/%s", buildInfo.ProjectID, buildInfo.Version, buildInfo.Build, download.Name), nil
 }
 
-func (s *fileServiceMock) FileExists(filepath string) bool {
-	if s.fileExistsHandler != nil {
-		return s.fileExistsHandler(s, filepath)
+func (s *paperServiceMock) DownloadExists(filepath string, buildInfo *paperapi.BuildInfo, artifact string) (bool, error) {
+	if s.downloadExistsHandler != nil {
+		return s.downloadExistsHandler(s, filepath, buildInfo, artifact)
 	}
 
-	return false
+	return false, nil
 }
 
-func (s *fileServiceMock) DeleteIfExists(filepath string) error {
-	if s.deleteIfExistsHandler != nil {
-		return s.deleteIfExistsHandler(s, filepath)
+func mockServiceFactory(serviceMock paperapi.Service) func(paperapi.Options) paperapi.Service {
+	return func(options paperapi.Options) paperapi.Service {
+		return serviceMock
 	}
+}
+
+type archiveServiceMock struct {
+	added    []string
+	pruned   []int
+	archived []files.ArchivedFile
+}
+
+func (s *archiveServiceMock) Add(dir string, filePath string, name string) error {
+	s.added = append(s.added, name)
 
-	s.deleteIfExistsCalled++
+	return nil
+}
+
+func (s *archiveServiceMock) List(dir string) ([]files.ArchivedFile, error) {
+	return s.archived, nil
+}
 
+func (s *archiveServiceMock) Restore(dir string, name string, filePath string) error {
 	return nil
 }
 
+func (s *archiveServiceMock) Prune(dir string, keep int) ([]files.ArchivedFile, error) {
+	s.pruned = append(s.pruned, keep)
+
+	return []files.ArchivedFile{}, nil
+}
+
 func TestRunMainProgram(t *testing.T) {
 	args := []string{"--skip-download"}
 
 	serviceMock := &paperServiceMock{}
-	fileService := &fileServiceMock{}
 
-	err := runMainProgram(serviceMock, fileService, args)
-	if err != nil && err.Error() != "no builds found" {
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, args)
+	if err != nil && !errors.Is(err, paperapi.ErrBuildNotFound) {
 		t.Error(err)
 	}
 }
@@ -89,14 +168,13 @@ func TestValidDownloadExistsExitsEarly(t *testing.T) {
 	args := []string{""}
 
 	serviceMock := &paperServiceMock{}
-	fileService := &fileServiceMock{}
 
-	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, unstable bool, versionPrefix string) (*paperapi.BuildInfo, error) {
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
 		buildInfo := &paperapi.BuildInfo{
 			Version: "1.20.2",
 			Build:   118,
-			Downloads: &paperapi.DownloadInfo{
-				Application: &paperapi.ApplicationInfo{
+			Downloads: paperapi.DownloadInfo{
+				paperapi.ApplicationArtifact: {
 					Name:   "paper.jar",
 					Sha256: "asdf",
 				},
@@ -107,11 +185,11 @@ func TestValidDownloadExistsExitsEarly(t *testing.T) {
 		return buildInfo, nil
 	}
 
-	serviceMock.downloadExistsHandler = func(s *paperServiceMock, filepath string, buildInfo *paperapi.BuildInfo) (bool, error) {
+	serviceMock.downloadExistsHandler = func(s *paperServiceMock, filepath string, buildInfo *paperapi.BuildInfo, artifact string) (bool, error) {
 		return true, nil
 	}
 
-	err := runMainProgram(serviceMock, fileService, args)
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, args)
 	if err != nil {
 		t.Error(err)
 	}
@@ -125,14 +203,13 @@ func TestSkipDownloadWorks(t *testing.T) {
 	args := []string{"--skip-download"}
 
 	serviceMock := &paperServiceMock{}
-	fileService := &fileServiceMock{}
 
-	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, unstable bool, versionPrefix string) (*paperapi.BuildInfo, error) {
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
 		buildInfo := &paperapi.BuildInfo{
 			Version: "1.20.2",
 			Build:   118,
-			Downloads: &paperapi.DownloadInfo{
-				Application: &paperapi.ApplicationInfo{
+			Downloads: paperapi.DownloadInfo{
+				paperapi.ApplicationArtifact: {
 					Name:   "paper.jar",
 					Sha256: "asdf",
 				},
@@ -143,7 +220,7 @@ func TestSkipDownloadWorks(t *testing.T) {
 		return buildInfo, nil
 	}
 
-	err := runMainProgram(serviceMock, fileService, args)
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, args)
 	if err != nil {
 		t.Error(err)
 	}
@@ -154,19 +231,51 @@ func TestSkipDownloadWorks(t *testing.T) {
 	}
 }
 
-func TestDeleteIfInvalidDownloadExists(t *testing.T) {
-	args := []string{}
+func TestExistingFileIsNotDeletedBeforeDownload(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+
+	err := os.WriteFile(jar, []byte("installed"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serviceMock := newLatestBuildServiceMock()
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filePath string) error {
+		s.ranDownload = true
+		return errors.New("connection reset")
+	}
+
+	_, err = runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--file", jar, "--keep", "0"})
+	if err == nil {
+		t.Error("Expected the failed download to be reported")
+	}
+
+	if !serviceMock.ranDownload {
+		t.Error("Expected download to run")
+	}
+
+	contents, err := os.ReadFile(jar)
+	if err != nil || string(contents) != "installed" {
+		t.Errorf("Expected the existing file to be kept after a failed download but got %q %v", contents, err)
+	}
+}
+
+func TestProjectIsPassedToService(t *testing.T) {
+	args := []string{"--skip-download", "--project", "velocity"}
 
 	serviceMock := &paperServiceMock{}
-	fileService := &fileServiceMock{}
 
-	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, unstable bool, versionPrefix string) (*paperapi.BuildInfo, error) {
+	requestedProject := ""
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		requestedProject = project
+
 		buildInfo := &paperapi.BuildInfo{
-			Version: "1.20.2",
-			Build:   118,
-			Downloads: &paperapi.DownloadInfo{
-				Application: &paperapi.ApplicationInfo{
-					Name:   "paper.jar",
+			ProjectID: project,
+			Version:   "3.3.0-SNAPSHOT",
+			Build:     390,
+			Downloads: paperapi.DownloadInfo{
+				paperapi.ApplicationArtifact: {
+					Name:   "velocity.jar",
 					Sha256: "asdf",
 				},
 			},
@@ -176,12 +285,424 @@ func TestDeleteIfInvalidDownloadExists(t *testing.T) {
 		return buildInfo, nil
 	}
 
-	err := runMainProgram(serviceMock, fileService, args)
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, args)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if requestedProject != "velocity" {
+		t.Errorf("Expected project to be velocity but was %s", requestedProject)
+	}
+}
+
+func TestTimeoutCancelsContext(t *testing.T) {
+	args := []string{"--timeout", "10ms"}
+
+	serviceMock := &paperServiceMock{}
+
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, args)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a deadline exceeded error but got %v", err)
+	}
+}
+
+func TestInvalidDownloadReturnsError(t *testing.T) {
+	args := []string{}
+
+	serviceMock := &paperServiceMock{}
+
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		return &paperapi.BuildInfo{
+			ProjectID: "paper",
+			Version:   "1.20.2",
+			Build:     118,
+			Downloads: paperapi.DownloadInfo{
+				paperapi.ApplicationArtifact: {
+					Name:   "paper.jar",
+					Sha256: "asdf",
+				},
+			},
+			Channel: "default",
+		}, nil
+	}
+
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filepath string) error {
+		return paperapi.ErrChecksumMismatch
+	}
+
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, args)
+	if !errors.Is(err, paperapi.ErrChecksumMismatch) {
+		t.Errorf("Expected a checksum mismatch error but got %v", err)
+	}
+}
+
+func TestRetryFlagsConfigureService(t *testing.T) {
+	args := []string{"--skip-download", "--retries", "5", "--retry-delay", "2s", "--retry-max-delay", "1m"}
+
+	serviceMock := &paperServiceMock{}
+
+	var apiOptions paperapi.Options
+	getService := func(options paperapi.Options) paperapi.Service {
+		apiOptions = options
+		return serviceMock
+	}
+
+	_, err := runMainProgram(getService, &archiveServiceMock{}, args)
+	if err != nil && !errors.Is(err, paperapi.ErrBuildNotFound) {
+		t.Error(err)
+	}
+
+	expected := paperapi.RetryPolicy{MaxRetries: 5, BaseDelay: 2 * time.Second, MaxDelay: time.Minute}
+	if apiOptions.RetryPolicy != expected {
+		t.Errorf("Expected retry policy %+v but got %+v", expected, apiOptions.RetryPolicy)
+	}
+}
+
+func TestNegativeRetryFlags(t *testing.T) {
+	for _, flag := range []string{"--retries=-1", "--retry-delay=-1s", "--retry-max-delay=-1s"} {
+		exitCode, err := runMainProgram(mockServiceFactory(&paperServiceMock{}), &archiveServiceMock{}, []string{"--skip-download", flag})
+		if !errors.Is(err, errUsage) || exitCode != exitUsage {
+			t.Errorf("Expected a usage error for %s but got %d %v", flag, exitCode, err)
+		}
+	}
+
+	_, err := runMainProgram(mockServiceFactory(newLatestBuildServiceMock()), &archiveServiceMock{}, []string{"--skip-download", "--retries", "0", "--retry-delay", "0"})
+	if err != nil {
+		t.Errorf("Expected no retries and no delay to be allowed but got %v", err)
+	}
+}
+
+func TestAPIURLFlags(t *testing.T) {
+	var apiOptions paperapi.Options
+	getService := func(options paperapi.Options) paperapi.Service {
+		apiOptions = options
+		return &paperServiceMock{}
+	}
+
+	runMainProgram(getService, &archiveServiceMock{}, []string{"--skip-download"})
+
+	if len(apiOptions.APIURLs) != 1 || apiOptions.APIURLs[0] != paperapi.DefaultAPIURL || apiOptions.Log != nil {
+		t.Errorf("Expected the official api without logging by default but got %v %v", apiOptions.APIURLs, apiOptions.Log)
+	}
+
+	runMainProgram(getService, &archiveServiceMock{}, []string{"--skip-download", "-v", "--api-url", "https://mirror.example.com/v2/", "--api-url", paperapi.DefaultAPIURL})
+
+	if len(apiOptions.APIURLs) != 2 || apiOptions.APIURLs[0] != "https://mirror.example.com/v2" || apiOptions.APIURLs[1] != paperapi.DefaultAPIURL {
+		t.Errorf("Expected the api urls in order without trailing slashes but got %v", apiOptions.APIURLs)
+	}
+
+	if apiOptions.Log == nil {
+		t.Error("Expected --verbose to log requests")
+	}
+
+	exitCode, err := runMainProgram(getService, &archiveServiceMock{}, []string{"--api-url", "mirror.example.com"})
+	if exitCode != exitUsage {
+		t.Errorf("Expected exit code %d for an api url without a scheme but got %d %v", exitUsage, exitCode, err)
+	}
+}
+
+func TestHTTPClientFlags(t *testing.T) {
+	var apiOptions paperapi.Options
+	getService := func(options paperapi.Options) paperapi.Service {
+		apiOptions = options
+		return &paperServiceMock{}
+	}
+
+	runMainProgram(getService, &archiveServiceMock{}, []string{"--skip-download", "--proxy", "socks5://proxy.example.com:1080"})
+
+	if apiOptions.HTTPClient == nil {
+		t.Error("Expected the api to use the configured http client")
+	}
+
+	tests := [][]string{
+		{"--proxy", "ftp://proxy.example.com"},
+		{"--ca-cert", filepath.Join(t.TempDir(), "missing.pem")},
+		{"--client-key", "client.key"},
+	}
+
+	for _, args := range tests {
+		exitCode, err := runMainProgram(getService, &archiveServiceMock{}, args)
+		if exitCode != exitUsage {
+			t.Errorf("%v: expected exit code %d but got %d %v", args, exitUsage, exitCode, err)
+		}
+	}
+}
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{errors.New("something went wrong"), exitError},
+		{&flags.Error{Type: flags.ErrUnknownFlag}, exitUsage},
+		{&paperapi.StatusError{StatusCode: 404, Err: paperapi.ErrVersionNotFound}, exitNotFound},
+		{fmt.Errorf("wrapped: %w", paperapi.ErrBuildNotFound), exitNotFound},
+		{paperapi.ErrProjectNotFound, exitNotFound},
+		{fmt.Errorf("%w: no versions of paper found", paperapi.ErrVersionNotFound), exitNotFound},
+		{&paperapi.StatusError{StatusCode: 429, Err: paperapi.ErrRateLimited}, exitRateLimited},
+		{&paperapi.ChecksumError{Expected: "abcd", Actual: "efgh"}, exitChecksumMismatch},
+		{context.DeadlineExceeded, exitCancelled},
+		{context.Canceled, exitCancelled},
+		{fmt.Errorf("%w: pre-hook: exit status 1", errHookFailed), exitHookFailed},
+	}
+
+	for _, test := range tests {
+		if code := exitCodeFor(test.err); code != test.expected {
+			t.Errorf("Expected exit code for %v to be %d but was %d", test.err, test.expected, code)
+		}
+	}
+}
+
+func TestPinnedVersionAndBuild(t *testing.T) {
+	args := []string{"--version", "1.20.4", "--build", "430"}
+
+	serviceMock := &paperServiceMock{}
+
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		t.Error("Didn't expect to look for the latest build when a build is pinned")
+
+		return nil, nil
+	}
+
+	serviceMock.getBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, version string, build int) (*paperapi.BuildInfo, error) {
+		if project != "paper" || version != "1.20.4" || build != 430 {
+			t.Errorf("Expected paper 1.20.4 build 430 to be requested but got %s %s build %d", project, version, build)
+		}
+
+		return &paperapi.BuildInfo{
+			ProjectID: project,
+			Version:   version,
+			Build:     build,
+			Downloads: paperapi.DownloadInfo{
+				paperapi.ApplicationArtifact: {
+					Name:   "paper-1.20.4-430.jar",
+					Sha256: "asdf",
+				},
+			},
+			Channel: "default",
+		}, nil
+	}
+
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, args)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !serviceMock.ranDownload {
+		t.Error("Expected the pinned build to be downloaded")
+	}
+}
+
+func TestVersionConstraint(t *testing.T) {
+	serviceMock := newLatestBuildServiceMock()
+	latestBuild := serviceMock.getLatestBuildHandler
+
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		if filter.Constraint == nil || !filter.Constraint.Matches("1.20.4") || filter.Constraint.Matches("1.20.3") || filter.Constraint.Matches("1.21") {
+			t.Errorf("Expected the --version constraint to be passed to the service but got %v", filter.Constraint)
+		}
+
+		return latestBuild(s, ctx, project, unstable, filter)
+	}
+
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--version", ">=1.20.2 <1.21, !=1.20.3"})
 	if err != nil {
 		t.Error(err)
 	}
+}
+
+func TestPinnedBuildRequiresVersion(t *testing.T) {
+	tests := [][]string{
+		{"--build", "430"},
+		{"--version", "~1.20", "--build", "430"},
+		{"--version", "1.20.4", "--build", "430", "--prefix", "1.20"},
+		{"--version", ">=1.20 <"},
+	}
+
+	for _, args := range tests {
+		_, err := runMainProgram(mockServiceFactory(&paperServiceMock{}), &archiveServiceMock{}, args)
+		if !errors.Is(err, errUsage) {
+			t.Errorf("Expected a usage error for %v but got %v", args, err)
+		}
+	}
+}
+
+func newLatestBuildServiceMock() *paperServiceMock {
+	serviceMock := &paperServiceMock{}
+
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		return &paperapi.BuildInfo{
+			ProjectID: project,
+			Version:   "1.20.4",
+			Build:     461,
+			Downloads: paperapi.DownloadInfo{
+				paperapi.ApplicationArtifact: {
+					Name:   "paper-1.20.4-461.jar",
+					Sha256: "asdf",
+				},
+			},
+			Channel: "default",
+		}, nil
+	}
+
+	return serviceMock
+}
+
+func TestUpdateExitCodes(t *testing.T) {
+	serviceMock := newLatestBuildServiceMock()
+
+	exitCode, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{})
+	if err != nil || exitCode != exitUpdated {
+		t.Errorf("Expected exit code %d for a download but got %d %v", exitUpdated, exitCode, err)
+	}
+
+	exitCode, err = runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--skip-download"})
+	if err != nil || exitCode != exitUpdateSkipped {
+		t.Errorf("Expected exit code %d for a skipped download but got %d %v", exitUpdateSkipped, exitCode, err)
+	}
+
+	serviceMock.downloadExistsHandler = func(s *paperServiceMock, filepath string, buildInfo *paperapi.BuildInfo, artifact string) (bool, error) {
+		return true, nil
+	}
+
+	exitCode, err = runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{})
+	if err != nil || exitCode != exitUpToDate {
+		t.Errorf("Expected exit code %d when already up to date but got %d %v", exitUpToDate, exitCode, err)
+	}
+
+	serviceMock.downloadExistsHandler = nil
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filepath string) error {
+		return paperapi.ErrChecksumMismatch
+	}
+
+	exitCode, err = runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{})
+	if err == nil || exitCode != exitChecksumMismatch {
+		t.Errorf("Expected exit code %d for an invalid download but got %d %v", exitChecksumMismatch, exitCode, err)
+	}
+}
+
+func TestUpdateResultJSON(t *testing.T) {
+	opts := &programArgs{Project: "paper", Filename: "paper.jar", Artifact: paperapi.ApplicationArtifact}
+
+	result, err := runUpdate(context.Background(), newLatestBuildServiceMock(), &archiveServiceMock{}, opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+
+	err = printJSON(out, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := map[string]any{}
+
+	err = json.Unmarshal(out.Bytes(), &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{
+		"action":  actionDownloaded,
+		"project": "paper",
+		"file":    "paper.jar",
+		"sha256":  "asdf",
+	}
+
+	for key, value := range expected {
+		if decoded[key] != value {
+			t.Errorf("Expected %s to be %v but was %v", key, value, decoded[key])
+		}
+	}
+
+	build, ok := decoded["build"].(map[string]any)
+	if !ok || build["version"] != "1.20.4" || build["build"] != float64(461) {
+		t.Errorf("Expected the resolved build to be included but got %v", decoded["build"])
+	}
+}
+
+func TestReplacedJarIsArchived(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	writeServerJar(t, jar, "1.20.2-318-abcdef0")
+
+	archiveService := &archiveServiceMock{}
+
+	_, err := runMainProgram(mockServiceFactory(newLatestBuildServiceMock()), archiveService, []string{"--file", jar, "--keep", "5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(archiveService.added) != 1 || archiveService.added[0] != "1.20.2-318.jar" {
+		t.Errorf("Expected the replaced jar to be archived as 1.20.2-318.jar but got %v", archiveService.added)
+	}
+
+	if len(archiveService.pruned) != 1 || archiveService.pruned[0] != 5 {
+		t.Errorf("Expected the archive to be pruned to 5 jars but got %v", archiveService.pruned)
+	}
+
+	archiveService = &archiveServiceMock{}
+
+	_, err = runMainProgram(mockServiceFactory(newLatestBuildServiceMock()), archiveService, []string{"--file", jar, "--keep", "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(archiveService.added) != 0 {
+		t.Errorf("Expected nothing to be archived with --keep 0 but got %v", archiveService.added)
+	}
+}
+
+func TestArtifactSelection(t *testing.T) {
+	serviceMock := newLatestBuildServiceMock()
+
+	latestBuild := serviceMock.getLatestBuildHandler
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		buildInfo, err := latestBuild(s, ctx, project, unstable, filter)
+		buildInfo.Downloads[paperapi.MojangMappingsArtifact] = &paperapi.ArtifactInfo{
+			Name:   "paper-mojang-mappings-1.20.4-461.txt",
+			Sha256: "efgh",
+		}
+
+		return buildInfo, err
+	}
+
+	downloadedArtifact := ""
+	downloadedFile := ""
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filepath string) error {
+		downloadedArtifact = artifact
+		downloadedFile = filepath
+
+		return nil
+	}
+
+	archiveService := &archiveServiceMock{}
+
+	_, err := runMainProgram(mockServiceFactory(serviceMock), archiveService, []string{"--artifact", "mojang-mappings"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if downloadedArtifact != paperapi.MojangMappingsArtifact {
+		t.Errorf("Expected the mojang-mappings artifact to be downloaded but got %s", downloadedArtifact)
+	}
+
+	if downloadedFile != "paper-mojang-mappings-1.20.4-461.txt" {
+		t.Errorf("Expected the mappings to be downloaded to their own name but got %s", downloadedFile)
+	}
+
+	if len(archiveService.added) != 0 {
+		t.Errorf("Expected only jars to be archived but got %v", archiveService.added)
+	}
 
-	if fileService.deleteIfExistsCalled != 1 {
-		t.Error("Expected delete if exists to be called once")
+	exitCode, err := runMainProgram(mockServiceFactory(serviceMock), archiveService, []string{"--artifact", "server-sources"})
+	if !errors.Is(err, paperapi.ErrArtifactNotFound) || exitCode != exitNotFound {
+		t.Errorf("Expected an artifact not found error but got %d %v", exitCode, err)
 	}
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sprpgmr/papermc-fetch/webhook"
+)
+
+// webhookTimeout is how long webhooks have to respond, even after the update's own context is done
+const webhookTimeout = 30 * time.Second
+
+// newNotifier returns a notifier for the --webhook urls and the webhooks listed in cfg, which may be nil
+func newNotifier(opts *programArgs, cfg *config) (webhook.Notifier, error) {
+	hooks := []webhook.Hook{}
+
+	template := ""
+	if len(opts.WebhookTemplate) > 0 {
+		b, err := os.ReadFile(opts.WebhookTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: couldn't read webhook template: %w", errUsage, err)
+		}
+
+		template = string(b)
+	}
+
+	for _, url := range opts.Webhooks {
+		hooks = append(hooks, webhook.Hook{URL: url, Template: template})
+	}
+
+	if cfg != nil {
+		hooks = append(hooks, cfg.Webhooks...)
+	}
+
+	retryPolicy := webhook.RetryPolicy{
+		MaxRetries: opts.Retries,
+		Delay:      opts.RetryDelay,
+	}
+
+	notifier, err := webhook.GetNotifier(hooks, retryPolicy, opts.httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	return notifier, nil
+}
+
+// notify sends the event for result to the webhooks, if anything happened worth telling them about.
+// Webhooks failing doesn't fail the update, so any error is only logged.
+func notify(ctx context.Context, notifier webhook.Notifier, result *updateResult, log io.Writer) {
+	event := &webhook.Event{
+		Target:     result.Target,
+		Project:    result.Project,
+		File:       result.File,
+		OldVersion: result.OldVersion,
+		OldBuild:   result.OldBuild,
+		Error:      result.Error,
+		HookError:  result.HookError,
+		Time:       time.Now(),
+	}
+
+	switch result.Action {
+	case actionDownloaded:
+		event.Type = webhook.EventUpdated
+	case actionSkipped:
+		event.Type = webhook.EventAvailable
+	case actionFailed:
+		event.Type = webhook.EventFailed
+		if result.ExitCode == exitChecksumMismatch {
+			event.Type = webhook.EventVerificationFailed
+		}
+	default:
+		return
+	}
+
+	if result.Build != nil {
+		event.NewVersion = result.Build.Version
+		event.NewBuild = result.Build.Build
+		event.Channel = result.Build.Channel
+	}
+
+	// failures are often the update timing out or being interrupted, which should still be reported
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), webhookTimeout)
+	defer cancel()
+
+	err := notifier.Notify(ctx, event)
+	if err != nil {
+		fmt.Fprintln(log, "Couldn't send webhook: ", err)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+	"github.com/sprpgmr/papermc-fetch/webhook"
+)
+
+func newWebhookReceiver(t *testing.T, events *[]webhook.Event) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := webhook.Event{}
+
+		err := json.NewDecoder(r.Body).Decode(&event)
+		if err != nil {
+			t.Error(err)
+		}
+
+		*events = append(*events, event)
+	}))
+
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestWebhookIsSentAfterUpdate(t *testing.T) {
+	events := []webhook.Event{}
+	ts := newWebhookReceiver(t, &events)
+
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	writeServerJar(t, jar, "1.20.4-430-abcdef0")
+
+	_, err := runMainProgram(mockServiceFactory(newLatestBuildServiceMock()), &archiveServiceMock{}, []string{"--file", jar, "--webhook", ts.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event but received %d", len(events))
+	}
+
+	event := events[0]
+	if event.Type != webhook.EventUpdated || event.OldVersion != "1.20.4" || event.OldBuild != 430 || event.NewBuild != 461 || event.Channel != "default" {
+		t.Errorf("Expected an update from 1.20.4 #430 to #461 but received %+v", event)
+	}
+}
+
+func TestWebhookIsSentForInvalidDownload(t *testing.T) {
+	events := []webhook.Event{}
+	ts := newWebhookReceiver(t, &events)
+
+	serviceMock := newLatestBuildServiceMock()
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filePath string) error {
+		return &paperapi.ChecksumError{Expected: "asdf", Actual: "1234"}
+	}
+
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--webhook", ts.URL})
+	if err == nil {
+		t.Fatal("Expected the update to fail")
+	}
+
+	if len(events) != 1 || events[0].Type != webhook.EventVerificationFailed || len(events[0].Error) == 0 {
+		t.Errorf("Expected a verification-failed event with the error but received %+v", events)
+	}
+}
+
+func TestWebhookIsNotSentWhenUpToDate(t *testing.T) {
+	events := []webhook.Event{}
+	ts := newWebhookReceiver(t, &events)
+
+	serviceMock := newLatestBuildServiceMock()
+	serviceMock.downloadExistsHandler = func(s *paperServiceMock, filePath string, buildInfo *paperapi.BuildInfo, artifact string) (bool, error) {
+		return true, nil
+	}
+
+	_, err := runMainProgram(mockServiceFactory(serviceMock), &archiveServiceMock{}, []string{"--webhook", ts.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 0 {
+		t.Errorf("Expected no events but received %+v", events)
+	}
+}
+
+func TestConfigWebhooks(t *testing.T) {
+	events := []webhook.Event{}
+	ts := newWebhookReceiver(t, &events)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "papermc-fetch.yaml")
+
+	contents := `
+targets:
+  - name: lobby
+    file: ` + filepath.Join(dir, "lobby.jar") + `
+webhooks:
+  - url: ` + ts.URL + `
+    events: [available]
+`
+
+	err := os.WriteFile(path, []byte(contents), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = runMainProgram(mockServiceFactory(newLatestBuildServiceMock()), &archiveServiceMock{}, []string{"--config", path, "--skip-download"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || events[0].Type != webhook.EventAvailable || events[0].Target != "lobby" {
+		t.Errorf("Expected an available event for lobby but received %+v", events)
+	}
+}
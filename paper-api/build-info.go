@@ -1,60 +1,100 @@
 package paperapi
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
+	"time"
 )
 
 // BuildInfo contains information about a specific paper build.
 type BuildInfo struct {
-	Version   string        `json:"version"`
-	Channel   string        `json:"channel"`
-	Downloads *DownloadInfo `json:"downloads"`
-	Build     int           `json:"build"`
+	ProjectID string       `json:"project_id"`
+	Version   string       `json:"version"`
+	Channel   string       `json:"channel"`
+	Downloads DownloadInfo `json:"downloads"`
+	Build     int          `json:"build"`
+	Time      time.Time    `json:"time"`
+	Promoted  bool         `json:"promoted"`
+	Changes   []*Change    `json:"changes"`
 }
 
-// DownloadInfo contains information about the available downloads for a Build
-type DownloadInfo struct {
-	Application *ApplicationInfo `json:"application"`
+// Change contains information about a commit included in a build
+type Change struct {
+	Commit  string `json:"commit"`
+	Summary string `json:"summary"`
+	Message string `json:"message"`
 }
 
-// ApplicationInfo contains information about the available download, including file name, and sha256 hash
-type ApplicationInfo struct {
+// Names of the artifacts builds can have downloads for
+const (
+	ApplicationArtifact    = "application"
+	MojangMappingsArtifact = "mojang-mappings"
+)
+
+// DownloadInfo contains information about the available downloads for a Build, keyed by artifact name
+type DownloadInfo map[string]*ArtifactInfo
+
+// Application returns the information about the application download, or nil if the build doesn't have one
+func (d DownloadInfo) Application() *ArtifactInfo {
+	return d[ApplicationArtifact]
+}
+
+// Artifact returns the information about the named artifact's download, or ErrArtifactNotFound if the build doesn't have one
+func (d DownloadInfo) Artifact(name string) (*ArtifactInfo, error) {
+	artifact, ok := d[name]
+	if !ok || artifact == nil {
+		return nil, fmt.Errorf("%w: build has no %s download", ErrArtifactNotFound, name)
+	}
+
+	return artifact, nil
+}
+
+// ArtifactInfo contains information about an available download, including file name, and sha256 hash
+type ArtifactInfo struct {
 	Name   string `json:"name"`
 	Sha256 string `json:"sha256"`
 }
 
 // BuildInfoService provides methods for getting build info
 type BuildInfoService interface {
-	GetBuildInfo(version string, build int) (*BuildInfo, error)
+	GetBuildInfo(ctx context.Context, project string, version string, build int) (*BuildInfo, error)
 }
 
 type buildInfoServiceImpl struct {
-	baseURL string
+	client *apiClient
 }
 
-func newBuildInfoServiceImpl(baseURL string) *buildInfoServiceImpl {
+func newBuildInfoServiceImpl(client *apiClient) *buildInfoServiceImpl {
 	return &buildInfoServiceImpl{
-		baseURL: baseURL,
+		client: client,
 	}
 }
 
-// GetBuildInfo will get the build info for a specific version and build number
-func (s *buildInfoServiceImpl) GetBuildInfo(version string, build int) (*BuildInfo, error) {
+// GetBuildInfo will get the build info for a specific project, version and build number
+func (s *buildInfoServiceImpl) GetBuildInfo(ctx context.Context, project string, version string, build int) (*BuildInfo, error) {
+	if len(project) == 0 {
+		return nil, errors.New("project must be specified to get build info")
+	}
+
 	if len(version) == 0 {
 		return nil, errors.New("version must be specified to get build info")
 	}
 
-	url := fmt.Sprint(s.baseURL, "/versions/", version, "/builds/", build)
-	resp, err := http.Get(url)
+	path := fmt.Sprint(projectPath(project), "/versions/", version, "/builds/", build)
+	resp, err := s.client.get(ctx, path)
 	if err != nil {
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
+	err = checkStatus(resp, ErrBuildNotFound)
+	if err != nil {
+		return nil, err
+	}
+
 	dec := json.NewDecoder(resp.Body)
 
 	buildInfo := &BuildInfo{}
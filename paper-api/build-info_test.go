@@ -1,20 +1,36 @@
 package paperapi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 const jsonResponse = `{
 	"version": "v1.20.2",
 	"build": 318,
 	"channel": "default",
+	"time": "2023-12-01T10:30:00.000Z",
+	"promoted": true,
+	"changes": [
+	  {
+		"commit": "0123456789abcdef",
+		"summary": "Fix something",
+		"message": "Fix something\n\nIn more detail"
+	  }
+	],
 	"downloads": {
 	  "application": {
 		"name": "1.20.2-318.jar",
 		"sha256": "asdf"
+	  },
+	  "mojang-mappings": {
+		"name": "paper-mojang-mappings-1.20.2-318.txt",
+		"sha256": "ghjk"
 	  }
 	}
   }`
@@ -26,9 +42,9 @@ func TestGetBuildInfo(t *testing.T) {
 
 	defer ts.Close()
 
-	buildInfoService := newBuildInfoServiceImpl(ts.URL)
+	buildInfoService := newBuildInfoServiceImpl(newAPIClient([]string{ts.URL}, RetryPolicy{}))
 
-	buildInfo, err := buildInfoService.GetBuildInfo("1.20.2", 318)
+	buildInfo, err := buildInfoService.GetBuildInfo(context.Background(), "paper", "1.20.2", 318)
 	if err != nil {
 		t.Error(err)
 	}
@@ -49,15 +65,40 @@ func TestGetBuildInfo(t *testing.T) {
 		t.Errorf("build info missing downloads info")
 	}
 
-	if buildInfo.Downloads.Application == nil {
+	if buildInfo.Downloads.Application() == nil {
 		t.Errorf("build info missing downloads.application info")
 	}
 
-	if buildInfo.Downloads.Application.Name != "1.20.2-318.jar" {
-		t.Errorf("Expected application name to be 1.20.2-318.jar but was %s", buildInfo.Downloads.Application.Name)
+	if buildInfo.Downloads.Application().Name != "1.20.2-318.jar" {
+		t.Errorf("Expected application name to be 1.20.2-318.jar but was %s", buildInfo.Downloads.Application().Name)
+	}
+
+	if buildInfo.Downloads.Application().Sha256 != "asdf" {
+		t.Errorf("Expected application Sha256 to be asdf but was %s", buildInfo.Downloads.Application().Sha256)
+	}
+
+	mappings, err := buildInfo.Downloads.Artifact(MojangMappingsArtifact)
+	if err != nil {
+		t.Error(err)
+	} else if mappings.Name != "paper-mojang-mappings-1.20.2-318.txt" || mappings.Sha256 != "ghjk" {
+		t.Errorf("Expected mojang-mappings download but got %+v", mappings)
+	}
+
+	_, err = buildInfo.Downloads.Artifact("server-sources")
+	if !errors.Is(err, ErrArtifactNotFound) {
+		t.Errorf("Expected ErrArtifactNotFound for a missing artifact but got %v", err)
+	}
+
+	expectedTime := time.Date(2023, 12, 1, 10, 30, 0, 0, time.UTC)
+	if !buildInfo.Time.Equal(expectedTime) {
+		t.Errorf("Expected buildInfo.Time %v to equal %v", buildInfo.Time, expectedTime)
+	}
+
+	if !buildInfo.Promoted {
+		t.Error("Expected buildInfo.Promoted to be true")
 	}
 
-	if buildInfo.Downloads.Application.Sha256 != "asdf" {
-		t.Errorf("Expected application Sha256 to be asdf but was %s", buildInfo.Downloads.Application.Sha256)
+	if len(buildInfo.Changes) != 1 || buildInfo.Changes[0].Commit != "0123456789abcdef" || buildInfo.Changes[0].Summary != "Fix something" {
+		t.Errorf("Expected buildInfo.Changes to contain the commit but was %+v", buildInfo.Changes)
 	}
 }
@@ -1,55 +1,68 @@
 package paperapi
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"net/http"
 	"slices"
 )
 
 // BuildsList contains information about the available builds made for the version
 type BuildsList struct {
-	Version string `json:"version"`
-	Builds  []int  `json:"builds"`
+	ProjectID string `json:"project_id"`
+	Version   string `json:"version"`
+	Builds    []int  `json:"builds"`
 }
 
 // BuildsListService provides methods for getting a list of builds
 type BuildsListService interface {
-	GetBuildsList(version string) (*BuildsList, error)
+	GetBuildsList(ctx context.Context, project string, version string) (*BuildsList, error)
 }
 
 type buildsListServiceImpl struct {
-	baseURL string
+	client *apiClient
 }
 
-func newBuildsListServiceImpl(baseURL string) *buildsListServiceImpl {
+func newBuildsListServiceImpl(client *apiClient) *buildsListServiceImpl {
 	return &buildsListServiceImpl{
-		baseURL: baseURL,
+		client: client,
 	}
 }
 
-// GetBuildsList gets a list of builds for the version provided
-func (s *buildsListServiceImpl) GetBuildsList(version string) (*BuildsList, error) {
+// GetBuildsList gets a list of builds for the project and version provided
+func (s *buildsListServiceImpl) GetBuildsList(ctx context.Context, project string, version string) (*BuildsList, error) {
+	if len(project) == 0 {
+		return nil, errors.New("must specify project to get builds for")
+	}
+
 	if len(version) == 0 {
 		return nil, errors.New("must specify version to get builds for")
 	}
 
-	buildsURL := s.baseURL + "/versions/" + version
+	buildsPath := projectPath(project) + "/versions/" + version
 
-	resp, err := http.Get(buildsURL)
+	resp, err := s.client.get(ctx, buildsPath)
 	if err != nil {
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
+	err = checkStatus(resp, ErrVersionNotFound)
+	if err != nil {
+		return nil, err
+	}
+
 	dec := json.NewDecoder(resp.Body)
 
 	buildsList := &BuildsList{}
 
 	err = dec.Decode(buildsList)
+	if err != nil {
+		return nil, err
+	}
 
 	slices.Sort[[]int](buildsList.Builds)
 
-	return buildsList, err
+	return buildsList, nil
 }
@@ -1,6 +1,7 @@
 package paperapi
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -15,9 +16,9 @@ func TestGetBuildsList(t *testing.T) {
 
 	defer ts.Close()
 
-	buildsListServiceImpl := newBuildsListServiceImpl(ts.URL)
+	buildsListServiceImpl := newBuildsListServiceImpl(newAPIClient([]string{ts.URL}, RetryPolicy{}))
 
-	buildsList, err := buildsListServiceImpl.GetBuildsList("1.20.2")
+	buildsList, err := buildsListServiceImpl.GetBuildsList(context.Background(), "paper", "1.20.2")
 	if err != nil {
 		t.Error(err)
 	}
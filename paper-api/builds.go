@@ -0,0 +1,76 @@
+package paperapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// VersionBuilds contains the full build info of every build made for the version
+type VersionBuilds struct {
+	ProjectID string       `json:"project_id"`
+	Version   string       `json:"version"`
+	Builds    []*BuildInfo `json:"builds"`
+}
+
+// BuildsService provides methods for getting the build info of every build of a version in one request
+type BuildsService interface {
+	GetBuilds(ctx context.Context, project string, version string) (*VersionBuilds, error)
+}
+
+type buildsServiceImpl struct {
+	client *apiClient
+}
+
+func newBuildsServiceImpl(client *apiClient) *buildsServiceImpl {
+	return &buildsServiceImpl{
+		client: client,
+	}
+}
+
+// GetBuilds gets the build info of every build for the project and version provided, sorted by build number
+func (s *buildsServiceImpl) GetBuilds(ctx context.Context, project string, version string) (*VersionBuilds, error) {
+	if len(project) == 0 {
+		return nil, errors.New("must specify project to get builds for")
+	}
+
+	if len(version) == 0 {
+		return nil, errors.New("must specify version to get builds for")
+	}
+
+	buildsPath := projectPath(project) + "/versions/" + version + "/builds"
+
+	resp, err := s.client.get(ctx, buildsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	err = checkStatus(resp, ErrVersionNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(resp.Body)
+
+	versionBuilds := &VersionBuilds{}
+
+	err = dec.Decode(versionBuilds)
+	if err != nil {
+		return nil, err
+	}
+
+	// the builds endpoint only includes the project and version once, so copy them into each build
+	for _, build := range versionBuilds.Builds {
+		build.ProjectID = versionBuilds.ProjectID
+		build.Version = versionBuilds.Version
+	}
+
+	slices.SortFunc[[]*BuildInfo](versionBuilds.Builds, func(a, b *BuildInfo) int {
+		return a.Build - b.Build
+	})
+
+	return versionBuilds, nil
+}
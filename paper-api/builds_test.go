@@ -0,0 +1,80 @@
+package paperapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const buildsJSONResponse = `{
+	"project_id": "paper",
+	"version": "1.20.4",
+	"builds": [
+	  {
+		"build": 461,
+		"channel": "default",
+		"downloads": {
+		  "application": {
+			"name": "paper-1.20.4-461.jar",
+			"sha256": "abcd"
+		  }
+		}
+	  },
+	  {
+		"build": 3,
+		"channel": "experimental",
+		"downloads": {
+		  "application": {
+			"name": "paper-1.20.4-3.jar",
+			"sha256": "efgh"
+		  }
+		}
+	  }
+	]
+  }`
+
+func TestGetBuilds(t *testing.T) {
+	requestedPath := ""
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		fmt.Fprint(w, buildsJSONResponse)
+	}))
+
+	defer ts.Close()
+
+	buildsService := newBuildsServiceImpl(newAPIClient([]string{ts.URL}, RetryPolicy{}))
+
+	versionBuilds, err := buildsService.GetBuilds(context.Background(), "paper", "1.20.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requestedPath != "/projects/paper/versions/1.20.4/builds" {
+		t.Errorf("Expected request path to be /projects/paper/versions/1.20.4/builds but was %s", requestedPath)
+	}
+
+	if len(versionBuilds.Builds) != 2 {
+		t.Fatalf("Expected 2 builds but there were %d", len(versionBuilds.Builds))
+	}
+
+	first := versionBuilds.Builds[0]
+	if first.Build != 3 || first.Channel != "experimental" {
+		t.Errorf("Expected first build to be experimental build 3 but was %s build %d", first.Channel, first.Build)
+	}
+
+	last := versionBuilds.Builds[1]
+	if last.Build != 461 || last.Channel != "default" {
+		t.Errorf("Expected last build to be default build 461 but was %s build %d", last.Channel, last.Build)
+	}
+
+	if last.Version != "1.20.4" || last.ProjectID != "paper" {
+		t.Errorf("Expected builds to have the version and project copied in, got %s %s", last.ProjectID, last.Version)
+	}
+
+	if last.Downloads.Application().Sha256 != "abcd" {
+		t.Errorf("Expected application Sha256 to be abcd but was %s", last.Downloads.Application().Sha256)
+	}
+}
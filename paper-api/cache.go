@@ -0,0 +1,103 @@
+package paperapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// responseCache keeps the bodies of api responses in memory, so repeated requests for the same path can be revalidated
+// with If-None-Match and If-Modified-Since instead of downloading the response again
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+type cachedResponse struct {
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		entries: map[string]*cachedResponse{},
+	}
+}
+
+// do requests path with the validators of any cached response for it, returning the cached body when the server
+// responds 304 Not Modified, and caching successful responses that have validators.
+// Responses are cached by path rather than url, so they're shared by every base url.
+func (rc *responseCache) do(ctx context.Context, c *apiClient, path string) (*http.Response, error) {
+	rc.mu.Lock()
+	entry := rc.entries[path]
+	rc.mu.Unlock()
+
+	header := http.Header{}
+
+	if entry != nil {
+		if len(entry.etag) > 0 {
+			header.Set("If-None-Match", entry.etag)
+		}
+
+		if len(entry.lastModified) > 0 {
+			header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	resp, err := c.do(ctx, path, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+
+		return entry.response(resp.Request), nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+
+	if resp.StatusCode != http.StatusOK || (len(etag) == 0 && len(lastModified) == 0) {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	entry = &cachedResponse{
+		etag:         etag,
+		lastModified: lastModified,
+		header:       resp.Header,
+		body:         body,
+	}
+
+	rc.mu.Lock()
+	rc.entries[path] = entry
+	rc.mu.Unlock()
+
+	return entry.response(resp.Request), nil
+}
+
+// response returns a new 200 OK response for req with the cached body
+func (e *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
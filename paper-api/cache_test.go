@@ -0,0 +1,82 @@
+package paperapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCacheRevalidatesResponses(t *testing.T) {
+	requests := 0
+	revalidated := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"project_id":"paper"}`))
+	}))
+
+	defer ts.Close()
+
+	client := newAPIClient([]string{ts.URL}, RetryPolicy{})
+	client.cache = newResponseCache()
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.get(context.Background(), "/projects/paper")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != http.StatusOK || string(body) != `{"project_id":"paper"}` {
+			t.Errorf("Expected the cached body on request %d but got %s %s", i+1, resp.Status, body)
+		}
+	}
+
+	if requests != 3 || revalidated != 2 {
+		t.Errorf("Expected 2 of 3 requests to be revalidated but %d of %d were", revalidated, requests)
+	}
+}
+
+func TestCacheSkipsResponsesWithoutValidators(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.Header.Get("If-None-Match")) > 0 || len(r.Header.Get("If-Modified-Since")) > 0 {
+			t.Error("Expected no conditional request for a response without validators")
+		}
+
+		w.Write([]byte(`{}`))
+	}))
+
+	defer ts.Close()
+
+	client := newAPIClient([]string{ts.URL}, RetryPolicy{})
+	client.cache = newResponseCache()
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.get(context.Background(), "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	if len(client.cache.entries) != 0 {
+		t.Errorf("Expected nothing to be cached but got %d entries", len(client.cache.entries))
+	}
+}
@@ -0,0 +1,49 @@
+package paperapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// GetChangelog will return every build of the project after fromVersion build fromBuild, up to and including toVersion build toBuild,
+// oldest first, so the changes of each build can be shown. The builds of every version in between are included.
+func (s *serviceImpl) GetChangelog(ctx context.Context, project string, fromVersion string, fromBuild int, toVersion string, toBuild int) ([]*BuildInfo, error) {
+	versions, err := s.versionsListService.GetVersionsList(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	from := slices.Index[[]string](versions.Versions, fromVersion)
+	if from < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrVersionNotFound, fromVersion)
+	}
+
+	to := slices.Index[[]string](versions.Versions, toVersion)
+	if to < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrVersionNotFound, toVersion)
+	}
+
+	builds := make([]*BuildInfo, 0)
+
+	for i := from; i <= to; i++ {
+		versionBuilds, err := s.buildsService.GetBuilds(ctx, project, versions.Versions[i])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, build := range versionBuilds.Builds {
+			if i == from && build.Build <= fromBuild {
+				continue
+			}
+
+			if i == to && build.Build > toBuild {
+				continue
+			}
+
+			builds = append(builds, build)
+		}
+	}
+
+	return builds, nil
+}
@@ -0,0 +1,71 @@
+package paperapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetChangelog(t *testing.T) {
+	versionsListMock := versionsListServiceMock{}
+	versionsListMock.getVersionsListHandler = func(s versionsListServiceMock) (*VersionsList, error) {
+		return &VersionsList{Versions: []string{"1.20.1", "1.20.2", "1.20.3", "1.20.4"}}, nil
+	}
+
+	buildsMock := buildsServiceMock{}
+	buildsMock.getBuildsHandler = func(s buildsServiceMock, version string) (*VersionBuilds, error) {
+		versionBuilds := &VersionBuilds{Version: version}
+
+		for build := 1; build <= 3; build++ {
+			versionBuilds.Builds = append(versionBuilds.Builds, &BuildInfo{
+				Version: version,
+				Build:   build,
+				Changes: []*Change{{Commit: version, Summary: "change"}},
+			})
+		}
+
+		return versionBuilds, nil
+	}
+
+	service := newServiceImpl(nil, versionsListMock, nil, buildsMock, nil, nil)
+
+	builds, err := service.GetChangelog(context.Background(), "paper", "1.20.2", 2, "1.20.4", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		version string
+		build   int
+	}{
+		{"1.20.2", 3},
+		{"1.20.3", 1},
+		{"1.20.3", 2},
+		{"1.20.3", 3},
+		{"1.20.4", 1},
+	}
+
+	if len(builds) != len(expected) {
+		t.Fatalf("Expected %d builds but got %d", len(expected), len(builds))
+	}
+
+	for i, build := range builds {
+		if build.Version != expected[i].version || build.Build != expected[i].build {
+			t.Errorf("Expected build %d to be %s #%d but was %s #%d", i, expected[i].version, expected[i].build, build.Version, build.Build)
+		}
+	}
+
+	builds, err = service.GetChangelog(context.Background(), "paper", "1.20.4", 1, "1.20.4", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(builds) != 2 || builds[0].Build != 2 || builds[1].Build != 3 {
+		t.Errorf("Expected builds 2 and 3 of 1.20.4 but got %+v", builds)
+	}
+
+	_, err = service.GetChangelog(context.Background(), "paper", "1.19.4", 1, "1.20.4", 3)
+	if !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Expected ErrVersionNotFound for an unknown version but got %v", err)
+	}
+}
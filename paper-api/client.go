@@ -0,0 +1,129 @@
+package paperapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sprpgmr/papermc-fetch/httpclient"
+)
+
+// apiClient performs requests against the papermc api, retrying transient failures according to its retry policy.
+// Requests go to the first base url, failing over to the next on connection errors and 5xx responses.
+// A single apiClient is shared by every service.
+type apiClient struct {
+	baseURLs    []string
+	retryPolicy RetryPolicy
+	httpClient  *http.Client
+	cache       *responseCache
+	log         io.Writer
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+func newAPIClient(baseURLs []string, retryPolicy RetryPolicy) *apiClient {
+	return &apiClient{
+		baseURLs:    baseURLs,
+		retryPolicy: retryPolicy,
+		httpClient:  http.DefaultClient,
+		log:         io.Discard,
+		sleep:       httpclient.Sleep,
+	}
+}
+
+// get performs a GET request against path, relative to the base url, which is cancelled when ctx is done.
+// If the client has a cache, the response is revalidated against the cached response instead of being fetched again.
+func (c *apiClient) get(ctx context.Context, path string) (*http.Response, error) {
+	if c.cache == nil {
+		return c.getRange(ctx, path, 0)
+	}
+
+	return c.cache.do(ctx, c, path)
+}
+
+// getRange performs a GET request against path asking for the content from offset onwards, which is cancelled when ctx is done.
+// The server may ignore the range and respond with the full content, so callers must check the response status.
+// Responses are never cached, so downloads should always use getRange.
+func (c *apiClient) getRange(ctx context.Context, path string, offset int64) (*http.Response, error) {
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	return c.do(ctx, path, header)
+}
+
+// do sends a GET request for path with header, retrying connection errors, 5xx and 429 responses until the retry policy gives up.
+// Each attempt starts again from the first base url.
+func (c *apiClient) do(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.failover(ctx, path, header)
+		if attempt >= c.retryPolicy.MaxRetries || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := c.retryPolicy.delay(attempt, resp)
+
+		if resp != nil {
+			// drain the body so the connection can be reused for the retry
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		err = c.sleep(ctx, delay)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// failover sends a GET request for path to each base url in turn, until one responds without a connection error or 5xx
+// or there are none left to try. The base url that served the response is logged.
+func (c *apiClient) failover(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	for i, baseURL := range c.baseURLs {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header = header.Clone()
+
+		resp, err := c.httpClient.Do(req)
+
+		last := i == len(c.baseURLs)-1
+		if last || !shouldFailover(ctx, resp, err) {
+			if err == nil {
+				fmt.Fprintf(c.log, "GET %s served by %s (%s)\n", path, baseURL, resp.Status)
+			}
+
+			return resp, err
+		}
+
+		if err != nil {
+			fmt.Fprintf(c.log, "GET %s failed on %s: %s, trying %s\n", path, baseURL, err, c.baseURLs[i+1])
+		} else {
+			fmt.Fprintf(c.log, "GET %s failed on %s: %s, trying %s\n", path, baseURL, resp.Status, c.baseURLs[i+1])
+
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}
+
+	return nil, errors.New("no api urls to request")
+}
+
+// shouldFailover returns true if the response or error from a base url means the next base url should be tried instead
+func shouldFailover(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return resp.StatusCode >= 500
+}
+
+// projectPath returns the path of the project endpoint for the project provided
+func projectPath(project string) string {
+	return "/projects/" + project
+}
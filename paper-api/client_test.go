@@ -0,0 +1,246 @@
+package paperapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(url string, retryPolicy RetryPolicy, delays *[]time.Duration) *apiClient {
+	client := newAPIClient([]string{url}, retryPolicy)
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+
+	return client
+}
+
+func TestClientRetriesTransientFailures(t *testing.T) {
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		switch requests {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+
+	defer ts.Close()
+
+	delays := []time.Duration{}
+	client := newTestClient(ts.URL, RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Minute}, &delays)
+
+	resp, err := client.get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the request to eventually succeed but got %s", resp.Status)
+	}
+
+	if requests != 3 {
+		t.Errorf("Expected 3 requests but there were %d", requests)
+	}
+
+	if len(delays) != 2 || delays[1] != 2*time.Second {
+		t.Errorf("Expected to wait twice, honouring Retry-After the second time, but waited %v", delays)
+	}
+}
+
+func TestClientGivesUpAfterMaxRetries(t *testing.T) {
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer ts.Close()
+
+	delays := []time.Duration{}
+	client := newTestClient(ts.URL, RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Second}, &delays)
+
+	resp, err := client.get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the last response to be returned but got %s", resp.Status)
+	}
+
+	if requests != 3 {
+		t.Errorf("Expected 3 requests but there were %d", requests)
+	}
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	defer ts.Close()
+
+	delays := []time.Duration{}
+	client := newTestClient(ts.URL, DefaultRetryPolicy(), &delays)
+
+	resp, err := client.get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if requests != 1 {
+		t.Errorf("Expected a 404 not to be retried but there were %d requests", requests)
+	}
+}
+
+func TestClientRetriesConnectionErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	delays := []time.Duration{}
+	client := newTestClient(url, RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Second}, &delays)
+
+	_, err := client.get(context.Background(), "/")
+	if err == nil {
+		t.Fatal("Expected an error connecting to a closed server")
+	}
+
+	if len(delays) != 2 {
+		t.Errorf("Expected connection errors to be retried twice but waited %d times", len(delays))
+	}
+}
+
+func TestClientFailsOverToMirrors(t *testing.T) {
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedURL := closed.URL
+	closed.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	defer failing.Close()
+
+	paths := []string{}
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer mirror.Close()
+
+	log := &bytes.Buffer{}
+	delays := []time.Duration{}
+	client := newTestClient(closedURL, DefaultRetryPolicy(), &delays)
+	client.baseURLs = []string{closedURL, failing.URL + "/v2", mirror.URL + "/v2"}
+	client.log = log
+
+	resp, err := client.get(context.Background(), "/projects/paper")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || len(paths) != 1 || paths[0] != "/v2/projects/paper" {
+		t.Errorf("Expected the last mirror to serve /v2/projects/paper but got %s for %v", resp.Status, paths)
+	}
+
+	if len(delays) != 0 {
+		t.Errorf("Expected failing over not to wait but waited %v", delays)
+	}
+
+	if !strings.Contains(log.String(), "served by "+mirror.URL+"/v2") || !strings.Contains(log.String(), "failed on "+failing.URL+"/v2: 502 Bad Gateway") {
+		t.Errorf("Expected the failures and the mirror that served the request to be logged but got %q", log)
+	}
+}
+
+func TestClientDoesNotFailOverClientErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	defer ts.Close()
+
+	requests := 0
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+
+	defer mirror.Close()
+
+	delays := []time.Duration{}
+	client := newTestClient(ts.URL, DefaultRetryPolicy(), &delays)
+	client.baseURLs = append(client.baseURLs, mirror.URL)
+
+	resp, err := client.get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound || requests != 0 {
+		t.Errorf("Expected the 404 to be returned without trying the mirror but got %s after %d mirror requests", resp.Status, requests)
+	}
+}
+
+func TestClientRetriesFromFirstMirror(t *testing.T) {
+	requests := []string{}
+
+	newServer := func(name string, status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, name)
+			w.WriteHeader(status)
+		}))
+	}
+
+	primary := newServer("primary", http.StatusServiceUnavailable)
+	defer primary.Close()
+
+	mirror := newServer("mirror", http.StatusInternalServerError)
+	defer mirror.Close()
+
+	delays := []time.Duration{}
+	client := newTestClient(primary.URL, RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Second}, &delays)
+	client.baseURLs = append(client.baseURLs, mirror.URL)
+
+	resp, err := client.get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected the last mirror's response once retries were used up but got %s", resp.Status)
+	}
+
+	if strings.Join(requests, ",") != "primary,mirror,primary,mirror" || len(delays) != 1 {
+		t.Errorf("Expected each retry to fail over through every mirror after waiting but requested %v after waiting %v", requests, delays)
+	}
+}
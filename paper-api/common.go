@@ -1,15 +1,67 @@
 package paperapi
 
-import "github.com/sprpgmr/papermc-fetch/files"
+import (
+	"io"
+	"net/http"
 
-const baseURL = "https://api.papermc.io/v2/projects/paper"
+	"github.com/sprpgmr/papermc-fetch/files"
+)
+
+// DefaultAPIURL is the base url of the official papermc api
+const DefaultAPIURL = "https://api.papermc.io/v2"
+
+// DefaultProject is the project used when none is specified
+const DefaultProject = "paper"
+
+// Options configures how the paper api service talks to the api
+type Options struct {
+	RetryPolicy RetryPolicy
+
+	// APIURLs are the base urls of the api and its mirrors, tried in order, failing over on connection errors and 5xx responses
+	APIURLs []string
+
+	// HTTPClient sends every request, or nil to use http.DefaultClient
+	HTTPClient *http.Client
+
+	// Log receives a line for every request saying which api url served it, or nil to discard them
+	Log io.Writer
+
+	// CacheResponses keeps api responses in memory and revalidates them on later requests, for long running processes
+	CacheResponses bool
+}
+
+// DefaultOptions returns the options used when none are configured
+func DefaultOptions() Options {
+	return Options{
+		RetryPolicy: DefaultRetryPolicy(),
+		APIURLs:     []string{DefaultAPIURL},
+	}
+}
 
 // GetPaperAPIService builds dependencies and passes them into the PaperApiServiceImpl for use
-func GetPaperAPIService() Service {
+func GetPaperAPIService(options Options) Service {
+	apiURLs := options.APIURLs
+	if len(apiURLs) == 0 {
+		apiURLs = []string{DefaultAPIURL}
+	}
+
+	client := newAPIClient(apiURLs, options.RetryPolicy)
+	if options.HTTPClient != nil {
+		client.httpClient = options.HTTPClient
+	}
+
+	if options.Log != nil {
+		client.log = options.Log
+	}
+
+	if options.CacheResponses {
+		client.cache = newResponseCache()
+	}
 
-	versionsListService := newVersionsListServiceImpl(baseURL)
-	buildsListService := newBuildsListServiceImpl(baseURL)
-	buildInfoService := newBuildInfoServiceImpl(baseURL)
+	versionsListService := newVersionsListServiceImpl(client)
+	buildsListService := newBuildsListServiceImpl(client)
+	buildInfoService := newBuildInfoServiceImpl(client)
+	buildsService := newBuildsServiceImpl(client)
 
-	return newServiceImpl(buildInfoService, versionsListService, buildsListService, files.GetFileService(), baseURL)
+	return newServiceImpl(buildInfoService, versionsListService, buildsListService, buildsService, files.GetFileService(), client)
 }
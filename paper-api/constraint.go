@@ -0,0 +1,242 @@
+package paperapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Constraint selects versions with an expression such as ">=1.20.2 <1.21", "~1.20" or "1.20.x, !=1.20.3".
+//
+// Conditions separated by spaces or commas must all match, and groups of conditions separated by "||" match if any
+// group does. A condition is a version, optionally preceded by one of =, !=, >, >=, <, <=, ~ (same minor line, so ~1.20.2
+// is >=1.20.2 <1.21) or ^ (same major line, so ^1.20 is >=1.20 <2). A version ending in .x or .* stands for its whole
+// release line, and * on its own matches every version. Ranges end before the first pre-release of their upper bound,
+// so <1.21 doesn't match 1.21-pre1.
+type Constraint struct {
+	raw    string
+	groups [][]versionCondition
+
+	// exact is the only version the constraint matches, if it's a single exact condition
+	exact string
+}
+
+type versionCondition func(v MinecraftVersion) bool
+
+// operators are the prefixes a condition can have, longest first so >= isn't read as >
+var operators = []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"}
+
+// ParseConstraint parses a constraint expression, returning an error describing the first invalid condition
+func ParseConstraint(constraint string) (*Constraint, error) {
+	c := &Constraint{raw: constraint}
+
+	groups := strings.Split(constraint, "||")
+
+	for _, group := range groups {
+		tokens := constraintTokens(group)
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q: empty condition", constraint)
+		}
+
+		conditions := make([]versionCondition, 0, len(tokens))
+
+		for _, token := range tokens {
+			condition, exact, err := parseCondition(token)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", constraint, err)
+			}
+
+			if len(groups) == 1 && len(tokens) == 1 {
+				c.exact = exact
+			}
+
+			conditions = append(conditions, condition)
+		}
+
+		c.groups = append(c.groups, conditions)
+	}
+
+	return c, nil
+}
+
+// constraintTokens splits a group of conditions at spaces and commas, keeping an operator with the version after it
+func constraintTokens(group string) []string {
+	fields := strings.Fields(strings.ReplaceAll(group, ",", " "))
+	tokens := make([]string, 0, len(fields))
+
+	for i := 0; i < len(fields); i++ {
+		token := fields[i]
+
+		if slices.Contains(operators, token) && i+1 < len(fields) {
+			i++
+			token += fields[i]
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens
+}
+
+// parseCondition parses a single condition, also returning the version it's for if it only matches that version
+func parseCondition(token string) (versionCondition, string, error) {
+	op := ""
+	for _, candidate := range operators {
+		if strings.HasPrefix(token, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	text := strings.TrimPrefix(token, op)
+
+	if text == "*" || text == "x" || text == "X" {
+		if op != "" && op != "=" && op != "==" {
+			return nil, "", fmt.Errorf("%s can't be used with *", op)
+		}
+
+		return func(v MinecraftVersion) bool { return true }, "", nil
+	}
+
+	wildcard := false
+	for _, suffix := range []string{".x", ".X", ".*"} {
+		if strings.HasSuffix(text, suffix) {
+			text = strings.TrimSuffix(text, suffix)
+			wildcard = true
+		}
+	}
+
+	version, err := ParseMinecraftVersion(text)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if wildcard && version.IsPreRelease() {
+		return nil, "", fmt.Errorf("%s can't be a wildcard", text)
+	}
+
+	if wildcard && (op == "~" || op == "^") {
+		return nil, "", fmt.Errorf("%s can't be used with a wildcard", op)
+	}
+
+	if wildcard {
+		return lineCondition(op, version), "", nil
+	}
+
+	switch op {
+	case "~":
+		// like semver, ~1 has no minor version to stay on, so it allows the whole 1.X line the same as ^1
+		if len(version.Numbers) == 1 {
+			return rangeCondition(version, bump(version, 0)), "", nil
+		}
+
+		return rangeCondition(version, bump(version, 1)), "", nil
+	case "^":
+		return rangeCondition(version, bump(version, 0)), "", nil
+	case "", "=", "==":
+		return compareCondition(op, version), text, nil
+	}
+
+	return compareCondition(op, version), "", nil
+}
+
+// compareCondition compares versions against version, where an upper or lower bound that's a release excludes
+// its pre-releases
+func compareCondition(op string, version MinecraftVersion) versionCondition {
+	bound := version
+	if !version.IsPreRelease() {
+		bound = firstPreRelease(version)
+	}
+
+	switch op {
+	case "!=":
+		return func(v MinecraftVersion) bool { return v.Compare(version) != 0 }
+	case ">":
+		return func(v MinecraftVersion) bool { return v.Compare(version) > 0 }
+	case ">=":
+		return func(v MinecraftVersion) bool { return v.Compare(version) >= 0 }
+	case "<":
+		return func(v MinecraftVersion) bool { return v.Compare(bound) < 0 }
+	case "<=":
+		return func(v MinecraftVersion) bool { return v.Compare(version) <= 0 }
+	}
+
+	return func(v MinecraftVersion) bool { return v.Compare(version) == 0 }
+}
+
+// lineCondition compares versions against the whole release line of line, e.g. 1.20.x
+func lineCondition(op string, line MinecraftVersion) versionCondition {
+	in := func(v MinecraftVersion) bool { return v.HasPrefix(line) }
+	before := func(v MinecraftVersion) bool { return !in(v) && v.Compare(firstPreRelease(line)) < 0 }
+
+	switch op {
+	case "!=":
+		return func(v MinecraftVersion) bool { return !in(v) }
+	case ">":
+		return func(v MinecraftVersion) bool { return !in(v) && !before(v) }
+	case ">=":
+		return func(v MinecraftVersion) bool { return !before(v) }
+	case "<":
+		return before
+	case "<=":
+		return func(v MinecraftVersion) bool { return in(v) || before(v) }
+	}
+
+	return in
+}
+
+// rangeCondition matches versions from lower up to, but not including, upper or any of its pre-releases
+func rangeCondition(lower MinecraftVersion, upper MinecraftVersion) versionCondition {
+	return func(v MinecraftVersion) bool {
+		return v.Compare(lower) >= 0 && v.Compare(firstPreRelease(upper)) < 0
+	}
+}
+
+// bump returns the release after version's line at component i, e.g. bumping 1.20.2 at 1 is 1.21
+func bump(version MinecraftVersion, i int) MinecraftVersion {
+	numbers := make([]int, i+1)
+	copy(numbers, version.Numbers)
+	numbers[i]++
+
+	return MinecraftVersion{Numbers: numbers, Kind: KindRelease}
+}
+
+// firstPreRelease returns a version older than every snapshot, pre-release and release candidate of version
+func firstPreRelease(version MinecraftVersion) MinecraftVersion {
+	return MinecraftVersion{Numbers: version.Numbers, Kind: KindSnapshot, Number: -1, weekly: version.weekly}
+}
+
+// Matches returns true if version is selected by the constraint. Versions that can't be parsed never match.
+func (c *Constraint) Matches(version string) bool {
+	v, err := ParseMinecraftVersion(version)
+	if err != nil {
+		return false
+	}
+
+	for _, group := range c.groups {
+		matched := true
+
+		for _, condition := range group {
+			if !condition(v) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Exact returns the version if the constraint matches exactly one version, such as 1.20.4, =1.20.4 or == 1.20.4
+func (c *Constraint) Exact() (string, bool) {
+	return c.exact, len(c.exact) > 0
+}
+
+// String returns the constraint as it was parsed
+func (c *Constraint) String() string {
+	return c.raw
+}
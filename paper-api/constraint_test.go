@@ -0,0 +1,201 @@
+package paperapi
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestConstraintMatches(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		// exact versions
+		{"1.20.4", "1.20.4", true},
+		{"1.20.4", "1.20.5", false},
+		{"1.20", "1.20.0", true},
+		{"1.20", "1.20.1", false},
+		{"=1.20.4", "1.20.4", true},
+		{"==1.20.4", "1.20.4", true},
+		{"1.21-pre1", "1.21-pre1", true},
+		{"1.21-pre1", "1.21", false},
+
+		// comparisons
+		{">1.20.2", "1.20.2", false},
+		{">1.20.2", "1.20.3", true},
+		{">=1.20.2", "1.20.2", true},
+		{">=1.20.2", "1.20.1", false},
+		{">=1.21", "1.21-rc1", false},
+		{"<1.21", "1.20.6", true},
+		{"<1.21", "1.21", false},
+		{"<1.21", "1.21-pre1", false},
+		{"<1.21", "3.3.0-SNAPSHOT", false},
+		{"<1.21-rc1", "1.21-pre3", true},
+		{"<=1.20.4", "1.20.4", true},
+		{"<=1.20.4", "1.20.5", false},
+		{"!=1.20.3", "1.20.3", false},
+		{"!=1.20.3", "1.20.4", true},
+		{">= 1.20.2", "1.20.2", true},
+
+		// ranges
+		{">=1.20.2 <1.21", "1.20.2", true},
+		{">=1.20.2 <1.21", "1.20.6", true},
+		{">=1.20.2 <1.21", "1.20.1", false},
+		{">=1.20.2 <1.21", "1.21", false},
+		{">=1.20.2, <1.21", "1.20.4", true},
+		{"~1", "1", true},
+		{"~1", "1.20.4", true},
+		{"~1", "2", false},
+		{"~1.20", "1.20", true},
+		{"~1.20", "1.20.6", true},
+		{"~1.20", "1.21", false},
+		{"~1.20", "1.21-pre1", false},
+		{"~1.20.2", "1.20.1", false},
+		{"~1.20.2", "1.20.2", true},
+		{"~1.20.2", "1.20.5-pre1", true},
+		{"~1.20.2", "1.21", false},
+		{"^1.20", "1.99", true},
+		{"^1.20", "1.19.4", false},
+		{"^1.20", "2.0", false},
+		{"^3.1.0", "3.3.0-SNAPSHOT", true},
+
+		// velocity's own -SNAPSHOT builds are releases, not minecraft snapshots
+		{"3.3.0", "3.3.0-SNAPSHOT", true},
+		{"3.3.0-SNAPSHOT", "3.3.0-SNAPSHOT", true},
+		{">=3.3.0", "3.3.0-SNAPSHOT", true},
+		{"<3.3.0", "3.3.0-SNAPSHOT", false},
+		{"<3.3.0", "3.2.0-SNAPSHOT", true},
+		{"~3.3", "3.3.0-SNAPSHOT", true},
+		{"~3.3", "3.3.1-SNAPSHOT", true},
+		{"~3.3", "3.4.0-SNAPSHOT", false},
+		{"3.3.x", "3.3.1-SNAPSHOT", true},
+
+		// wildcards
+		{"1.20.x", "1.20", true},
+		{"1.20.x", "1.20.4", true},
+		{"1.20.X", "1.20.4", true},
+		{"1.20.*", "1.20.4", true},
+		{"1.20.x", "1.2.4", false},
+		{"1.20.x", "1.21", false},
+		{"1.20.x", "1.20.5-rc1", true},
+		{"!=1.20.x", "1.20.4", false},
+		{"!=1.20.x", "1.21", true},
+		{">1.20.x", "1.20.6", false},
+		{">1.20.x", "1.21", true},
+		{">=1.20.x", "1.20", true},
+		{">=1.20.x", "1.19.4", false},
+		{"<1.20.x", "1.19.4", true},
+		{"<1.20.x", "1.20", false},
+		{"<=1.20.x", "1.20.6", true},
+		{"<=1.20.x", "1.21", false},
+		{"*", "1.20.4", true},
+		{"*", "24w14a", true},
+
+		// combinations
+		{"1.20.x, !=1.20.3", "1.20.3", false},
+		{"1.20.x, !=1.20.3", "1.20.4", true},
+		{"1.20.x !=1.20.3", "1.20.2", true},
+		{"1.19.x || 1.20.x", "1.19.4", true},
+		{"1.19.x || 1.20.x", "1.20.4", true},
+		{"1.19.x || 1.20.x", "1.21", false},
+		{"<1.19 || >=1.20.4", "1.18.2", true},
+		{"<1.19 || >=1.20.4", "1.19.4", false},
+		{"<1.19 || >=1.20.4", "1.20.4", true},
+
+		// versions that can't be parsed never match
+		{"*", "latest", false},
+		{">=1.20", "1.20-beta1", false},
+	}
+
+	for _, test := range tests {
+		constraint, err := ParseConstraint(test.constraint)
+		if err != nil {
+			t.Errorf("%s: %v", test.constraint, err)
+			continue
+		}
+
+		if actual := constraint.Matches(test.version); actual != test.expected {
+			t.Errorf("Expected %q matching %s to be %t", test.constraint, test.version, test.expected)
+		}
+	}
+}
+
+func TestParseInvalidConstraint(t *testing.T) {
+	constraints := []string{
+		"",
+		"   ",
+		",",
+		"||",
+		"1.20 ||",
+		">=",
+		">=1.20 <",
+		"latest",
+		">>1.20",
+		"=>1.20",
+		"~1.20.x",
+		">*",
+		"1.21-pre1.x",
+		"1.20.x.4",
+	}
+
+	for _, constraint := range constraints {
+		_, err := ParseConstraint(constraint)
+		if err == nil {
+			t.Errorf("Expected %q to be invalid", constraint)
+		}
+	}
+}
+
+func TestConstraintExact(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		exact      bool
+	}{
+		{"1.20.4", "1.20.4", true},
+		{"=1.20.4", "1.20.4", true},
+		{"==1.21-pre1", "1.21-pre1", true},
+		{" 1.20.4 ", "1.20.4", true},
+		{"= 1.20.4", "1.20.4", true},
+		{"== 1.20.4", "1.20.4", true},
+		{"3.3.0-SNAPSHOT", "3.3.0-SNAPSHOT", true},
+		{"*", "", false},
+		{"=1.20.x", "", false},
+		{"1.20.4, !=1.20.3", "", false},
+		{"1.20.x", "", false},
+		{"~1.20", "", false},
+		{">=1.20.4", "", false},
+		{"1.20.4 || 1.20.5", "", false},
+	}
+
+	for _, test := range tests {
+		constraint, err := ParseConstraint(test.constraint)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		version, exact := constraint.Exact()
+		if version != test.version || exact != test.exact {
+			t.Errorf("Expected %q to be exact %t %s but got %t %s", test.constraint, test.exact, test.version, exact, version)
+		}
+	}
+}
+
+func TestFilterVersionsWithConstraint(t *testing.T) {
+	input := &VersionsList{
+		Versions: []string{"1.19.4", "1.20", "1.20.1", "1.20.2", "1.20.3", "1.20.4", "1.20.5-pre1", "1.20.5", "1.21-pre1", "1.21"},
+	}
+
+	constraint, err := ParseConstraint(">=1.20.2 <1.21, !=1.20.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := filterVersions(VersionFilter{Constraint: constraint, ExcludePreReleases: true}, input)
+
+	expected := []string{"1.20.2", "1.20.4", "1.20.5"}
+	if !slices.Equal(output.Versions, expected) {
+		t.Errorf("Expected %v but got %v", expected, output.Versions)
+	}
+}
@@ -0,0 +1,80 @@
+package paperapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrProjectNotFound is returned when the api doesn't know about the project requested
+	ErrProjectNotFound = errors.New("project not found")
+	// ErrVersionNotFound is returned when the api doesn't know about the version requested
+	ErrVersionNotFound = errors.New("version not found")
+	// ErrBuildNotFound is returned when the api doesn't know about the build, or the build's download, requested
+	ErrBuildNotFound = errors.New("build not found")
+	// ErrArtifactNotFound is returned when a build doesn't have a download for the artifact requested
+	ErrArtifactNotFound = errors.New("artifact not found")
+	// ErrRateLimited is returned when the api is still rate limiting requests after retrying
+	ErrRateLimited = errors.New("rate limited by the api")
+	// ErrChecksumMismatch is returned when a downloaded file doesn't match the sha256 hash provided by the api
+	ErrChecksumMismatch = errors.New("download doesn't match the expected sha256 hash")
+)
+
+// StatusError is returned when the api responds with an unsuccessful http status.
+// It wraps ErrRateLimited or the not found error for the resource requested where one applies.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+	Err        error
+}
+
+func (e *StatusError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s returned %s", e.Err, e.URL, e.Status)
+	}
+
+	return fmt.Sprintf("%s returned %s", e.URL, e.Status)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// ChecksumError is returned when a downloaded file doesn't match the sha256 hash provided by the api, it wraps ErrChecksumMismatch
+type ChecksumError struct {
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s: %s had sha256 %s, expected %s", ErrChecksumMismatch, e.URL, e.Actual, e.Expected)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+// checkStatus returns a StatusError if resp isn't successful, wrapping notFound if the api responded with a 404
+func checkStatus(resp *http.Response, notFound error) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	statusErr := &StatusError{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		statusErr.Err = notFound
+	case http.StatusTooManyRequests:
+		statusErr.Err = ErrRateLimited
+	}
+
+	return statusErr
+}
@@ -0,0 +1,89 @@
+package paperapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServicesReturnTypedErrors(t *testing.T) {
+	status := http.StatusNotFound
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error": "Not found."}`))
+	}))
+
+	defer ts.Close()
+
+	client := newAPIClient([]string{ts.URL}, RetryPolicy{})
+	ctx := context.Background()
+
+	requests := map[string]func() error{
+		"versions list": func() error {
+			_, err := newVersionsListServiceImpl(client).GetVersionsList(ctx, "paper")
+			return err
+		},
+		"builds list": func() error {
+			_, err := newBuildsListServiceImpl(client).GetBuildsList(ctx, "paper", "1.20.4")
+			return err
+		},
+		"builds": func() error {
+			_, err := newBuildsServiceImpl(client).GetBuilds(ctx, "paper", "1.20.4")
+			return err
+		},
+		"build info": func() error {
+			_, err := newBuildInfoServiceImpl(client).GetBuildInfo(ctx, "paper", "1.20.4", 461)
+			return err
+		},
+	}
+
+	notFound := map[string]error{
+		"versions list": ErrProjectNotFound,
+		"builds list":   ErrVersionNotFound,
+		"builds":        ErrVersionNotFound,
+		"build info":    ErrBuildNotFound,
+	}
+
+	for name, request := range requests {
+		status = http.StatusNotFound
+
+		err := request()
+		if !errors.Is(err, notFound[name]) {
+			t.Errorf("Expected %s to return %v for a 404 but got %v", name, notFound[name], err)
+		}
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("Expected %s to return a StatusError but got %v", name, err)
+		}
+
+		if statusErr.StatusCode != http.StatusNotFound || len(statusErr.URL) == 0 {
+			t.Errorf("Expected %s error to carry the url and status but got %+v", name, statusErr)
+		}
+
+		status = http.StatusTooManyRequests
+
+		err = request()
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Expected %s to return ErrRateLimited for a 429 but got %v", name, err)
+		}
+
+		status = http.StatusInternalServerError
+
+		err = request()
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Expected %s to return a StatusError for a 500 but got %v", name, err)
+		}
+	}
+}
+
+func TestChecksumErrorIsChecksumMismatch(t *testing.T) {
+	var err error = &ChecksumError{URL: "http://localhost/paper.jar", Expected: "abcd", Actual: "efgh"}
+
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Error("Expected ChecksumError to be ErrChecksumMismatch")
+	}
+}
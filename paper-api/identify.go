@@ -0,0 +1,37 @@
+package paperapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// IdentifyJar will hash the file at filePath and search the builds of the project's versions that match filter, newest first,
+// for the build whose application download it is. ErrBuildNotFound is returned if it isn't any of their downloads.
+func (s *serviceImpl) IdentifyJar(ctx context.Context, project string, filePath string, filter VersionFilter) (*BuildInfo, error) {
+	hash, err := fileSha256(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.getFilteredVersionsList(ctx, project, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// each version costs a single request, as the builds endpoint includes the hashes of every build
+	for i := len(versions.Versions) - 1; i >= 0; i-- {
+		versionBuilds, err := s.buildsService.GetBuilds(ctx, project, versions.Versions[i])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, build := range versionBuilds.Builds {
+			application := build.Downloads.Application()
+			if application != nil && application.Sha256 == hash {
+				return build, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no build of %s has sha256 %s", ErrBuildNotFound, project, hash)
+}
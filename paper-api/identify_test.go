@@ -0,0 +1,78 @@
+package paperapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIdentifyJar(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+
+	err := os.WriteFile(jar, []byte("paper 1.20.2 build 318"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte("paper 1.20.2 build 318")))
+
+	versionsMock := versionsListServiceMock{
+		getVersionsListHandler: func(s versionsListServiceMock) (*VersionsList, error) {
+			return &VersionsList{ProjectID: "paper", Versions: []string{"1.20.1", "1.20.2", "1.20.4"}}, nil
+		},
+	}
+
+	searched := []string{}
+	buildsMock := buildsServiceMock{
+		getBuildsHandler: func(s buildsServiceMock, version string) (*VersionBuilds, error) {
+			searched = append(searched, version)
+
+			builds := &VersionBuilds{ProjectID: "paper", Version: version}
+			for build := 317; build <= 319; build++ {
+				sha := fmt.Sprintf("%s-%d", version, build)
+				if version == "1.20.2" && build == 318 {
+					sha = hash
+				}
+
+				builds.Builds = append(builds.Builds, &BuildInfo{
+					ProjectID: "paper",
+					Version:   version,
+					Build:     build,
+					Downloads: DownloadInfo{ApplicationArtifact: {Name: "paper.jar", Sha256: sha}},
+				})
+			}
+
+			return builds, nil
+		},
+	}
+
+	service := newServiceImpl(nil, versionsMock, nil, buildsMock, nil, nil)
+
+	buildInfo, err := service.IdentifyJar(context.Background(), "paper", jar, VersionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buildInfo.Version != "1.20.2" || buildInfo.Build != 318 {
+		t.Errorf("Expected 1.20.2 build 318 but got %s build %d", buildInfo.Version, buildInfo.Build)
+	}
+
+	if len(searched) != 2 || searched[0] != "1.20.4" {
+		t.Errorf("Expected versions to be searched newest first until found but searched %v", searched)
+	}
+
+	searched = nil
+
+	_, err = service.IdentifyJar(context.Background(), "paper", jar, VersionFilter{Prefix: "1.20.4"})
+	if !errors.Is(err, ErrBuildNotFound) {
+		t.Errorf("Expected ErrBuildNotFound outside the candidate versions but got %v", err)
+	}
+
+	if len(searched) != 1 {
+		t.Errorf("Expected only the candidate versions to be searched but searched %v", searched)
+	}
+}
@@ -0,0 +1,151 @@
+package paperapi
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionKind is the kind of release a version is, in the order they're released for the same version number
+type VersionKind int
+
+// Kinds of version, ordered so a snapshot comes before the pre-releases, release candidates and release of the same version
+const (
+	KindSnapshot VersionKind = iota
+	KindPreRelease
+	KindReleaseCandidate
+	KindRelease
+)
+
+// MinecraftVersion is a parsed version such as 1.20.4, 1.21-pre1, 1.21-rc1, 24w14a or 3.3.0-SNAPSHOT.
+// The -SNAPSHOT suffix of velocity's versions marks its own development builds, not a snapshot of minecraft,
+// so 3.3.0-SNAPSHOT is a release the same as 3.3.0.
+type MinecraftVersion struct {
+	// Numbers are the dotted components of the version, or the year and week of a weekly snapshot
+	Numbers []int
+
+	Kind VersionKind
+
+	// Number is the pre-release or release candidate number, or the letter of a weekly snapshot counting from 0
+	Number int
+
+	weekly bool
+	raw    string
+}
+
+var (
+	weeklySnapshotPattern = regexp.MustCompile(`^(\d+)w(\d+)([a-z])$`)
+	versionPattern        = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)*)(?:-snapshot|[- ]?(pre-release|pre|release candidate|rc)[- ]?(\d+))?$`)
+)
+
+// ParseMinecraftVersion parses version, returning an error if it isn't a form of version papermc uses
+func ParseMinecraftVersion(version string) (MinecraftVersion, error) {
+	if match := weeklySnapshotPattern.FindStringSubmatch(version); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		week, _ := strconv.Atoi(match[2])
+
+		return MinecraftVersion{
+			Numbers: []int{year, week},
+			Kind:    KindSnapshot,
+			Number:  int(match[3][0] - 'a'),
+			weekly:  true,
+			raw:     version,
+		}, nil
+	}
+
+	match := versionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return MinecraftVersion{}, fmt.Errorf("%s isn't a valid version", version)
+	}
+
+	v := MinecraftVersion{Kind: KindRelease, raw: version}
+
+	for _, part := range strings.Split(match[1], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return MinecraftVersion{}, fmt.Errorf("%s isn't a valid version", version)
+		}
+
+		v.Numbers = append(v.Numbers, n)
+	}
+
+	switch strings.ToLower(match[2]) {
+	case "pre", "pre-release":
+		v.Kind = KindPreRelease
+	case "rc", "release candidate":
+		v.Kind = KindReleaseCandidate
+	}
+
+	if len(match[3]) > 0 {
+		v.Number, _ = strconv.Atoi(match[3])
+	}
+
+	return v, nil
+}
+
+// String returns the version as it was parsed
+func (v MinecraftVersion) String() string {
+	return v.raw
+}
+
+// IsPreRelease returns true for snapshots, pre-releases and release candidates
+func (v MinecraftVersion) IsPreRelease() bool {
+	return v.Kind != KindRelease
+}
+
+// Compare returns -1 if v is older than other, 1 if it's newer, and 0 if they're the same version.
+// Missing components count as 0, so 1.20 and 1.20.0 are the same. Weekly snapshots can't be placed among
+// numbered versions, so they're older than all of them.
+func (v MinecraftVersion) Compare(other MinecraftVersion) int {
+	if v.weekly != other.weekly {
+		if v.weekly {
+			return -1
+		}
+
+		return 1
+	}
+
+	for i := 0; i < max(len(v.Numbers), len(other.Numbers)); i++ {
+		a, b := 0, 0
+
+		if i < len(v.Numbers) {
+			a = v.Numbers[i]
+		}
+
+		if i < len(other.Numbers) {
+			b = other.Numbers[i]
+		}
+
+		if a != b {
+			return cmp.Compare(a, b)
+		}
+	}
+
+	if v.Kind != other.Kind {
+		return cmp.Compare(v.Kind, other.Kind)
+	}
+
+	return cmp.Compare(v.Number, other.Number)
+}
+
+// HasPrefix returns true if v is in the release line of prefix, e.g. 1.20.4 and 1.20.5-pre1 are in 1.20.
+// A prefix that is itself a pre-release only matches that exact version.
+func (v MinecraftVersion) HasPrefix(prefix MinecraftVersion) bool {
+	if prefix.IsPreRelease() || v.weekly != prefix.weekly {
+		return v.Compare(prefix) == 0
+	}
+
+	if len(v.Numbers) < len(prefix.Numbers) {
+		return false
+	}
+
+	for i, n := range prefix.Numbers {
+		if v.Numbers[i] != n {
+			return false
+		}
+	}
+
+	return true
+}
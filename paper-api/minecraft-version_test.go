@@ -0,0 +1,118 @@
+package paperapi
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseMinecraftVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		numbers []int
+		kind    VersionKind
+		number  int
+	}{
+		{"1.20.4", []int{1, 20, 4}, KindRelease, 0},
+		{"1.21", []int{1, 21}, KindRelease, 0},
+		{"1.21-pre1", []int{1, 21}, KindPreRelease, 1},
+		{"1.14 Pre-Release 5", []int{1, 14}, KindPreRelease, 5},
+		{"1.20.5-rc2", []int{1, 20, 5}, KindReleaseCandidate, 2},
+		{"1.16 Release Candidate 1", []int{1, 16}, KindReleaseCandidate, 1},
+		{"3.3.0-SNAPSHOT", []int{3, 3, 0}, KindRelease, 0},
+		{"3.1.2-SNAPSHOT", []int{3, 1, 2}, KindRelease, 0},
+		{"24w14a", []int{24, 14}, KindSnapshot, 0},
+		{"23w51b", []int{23, 51}, KindSnapshot, 1},
+	}
+
+	for _, test := range tests {
+		v, err := ParseMinecraftVersion(test.version)
+		if err != nil {
+			t.Errorf("%s: %v", test.version, err)
+			continue
+		}
+
+		if !slices.Equal(v.Numbers, test.numbers) || v.Kind != test.kind || v.Number != test.number {
+			t.Errorf("%s: expected %v kind %d number %d but got %v kind %d number %d", test.version, test.numbers, test.kind, test.number, v.Numbers, v.Kind, v.Number)
+		}
+
+		if v.String() != test.version {
+			t.Errorf("Expected %s to print as itself but got %s", test.version, v)
+		}
+	}
+}
+
+func TestParseInvalidMinecraftVersion(t *testing.T) {
+	for _, version := range []string{"", "latest", "1.20.", "1..20", "1.20-beta1", "v1.20", "1.20-pre"} {
+		_, err := ParseMinecraftVersion(version)
+		if err == nil {
+			t.Errorf("Expected %q to be invalid", version)
+		}
+	}
+}
+
+func TestCompareMinecraftVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.20.4", "1.20.4", 0},
+		{"1.20", "1.20.0", 0},
+		{"1.2", "1.20", -1},
+		{"1.20.4", "1.21", -1},
+		{"1.21-pre1", "1.21", -1},
+		{"1.21-rc1", "1.21", -1},
+		{"1.21-pre2", "1.21-rc1", -1},
+		{"1.21-pre1", "1.21-pre2", -1},
+		{"1.21-pre10", "1.21-pre2", 1},
+		{"1.20.6", "1.21-pre1", -1},
+		{"3.3.0-SNAPSHOT", "3.3.0", 0},
+		{"3.3.0-SNAPSHOT", "3.3.0-pre1", 1},
+		{"3.3.0-SNAPSHOT", "3.2.0", 1},
+		{"3.3.0-SNAPSHOT", "3.2.0-SNAPSHOT", 1},
+		{"3.1.2-SNAPSHOT", "3.1.10-SNAPSHOT", -1},
+		{"24w14a", "24w14b", -1},
+		{"23w51b", "24w03a", -1},
+		{"24w14a", "1.0", -1},
+		{"1.21 Pre-Release 1", "1.21-pre1", 0},
+	}
+
+	for _, test := range tests {
+		a, _ := ParseMinecraftVersion(test.a)
+		b, _ := ParseMinecraftVersion(test.b)
+
+		if actual := a.Compare(b); actual != test.expected {
+			t.Errorf("Expected comparing %s to %s to be %d but was %d", test.a, test.b, test.expected, actual)
+		}
+
+		if actual := b.Compare(a); actual != -test.expected {
+			t.Errorf("Expected comparing %s to %s to be %d but was %d", test.b, test.a, -test.expected, actual)
+		}
+	}
+}
+
+func TestMinecraftVersionHasPrefix(t *testing.T) {
+	tests := []struct {
+		version, prefix string
+		expected        bool
+	}{
+		{"1.20.4", "1.20", true},
+		{"1.20", "1.20", true},
+		{"1.20.5-pre1", "1.20", true},
+		{"1.20.5-pre1", "1.20.5", true},
+		{"1.2.4", "1.20", false},
+		{"1.20", "1.20.4", false},
+		{"1.21-pre1", "1.21-pre1", true},
+		{"1.21-pre2", "1.21-pre1", false},
+		{"1.21", "1.21-pre1", false},
+		{"24w14a", "1.21", false},
+	}
+
+	for _, test := range tests {
+		v, _ := ParseMinecraftVersion(test.version)
+		prefix, _ := ParseMinecraftVersion(test.prefix)
+
+		if actual := v.HasPrefix(prefix); actual != test.expected {
+			t.Errorf("Expected %s having prefix %s to be %t", test.version, test.prefix, test.expected)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package paperapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests to the api are retried after transient failures
+type RetryPolicy struct {
+	// MaxRetries is how many times a request is retried before giving up, 0 disables retrying
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, which doubles for each retry after it
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries, including delays asked for by a Retry-After header
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		MaxDelay:   30 * time.Second,
+	}
+}
+
+// shouldRetry returns true if the response or error from an attempt is a transient failure worth retrying
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// don't retry once the caller has given up
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// delay returns how long to wait before the retry after attempt, preferring the server's Retry-After header if it sent one
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if retryAfter, ok := parseRetryAfter(resp, time.Now()); ok {
+		return min(retryAfter, p.MaxDelay)
+	}
+
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	// shifting back doesn't give the base delay if the backoff overflowed
+	backoff := p.BaseDelay << attempt
+	if backoff>>attempt != p.BaseDelay || backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+
+	// jitter the second half of the backoff so many clients don't retry in lockstep
+	half := backoff / 2
+	if half <= 0 {
+		return backoff
+	}
+
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// parseRetryAfter reads the Retry-After header of resp, which may be a number of seconds or an http date
+func parseRetryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	header := resp.Header.Get("Retry-After")
+	if len(header) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package paperapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		header   string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"0", 0, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second, true},
+		{now.Add(-10 * time.Second).Format(http.TimeFormat), 0, true},
+	}
+
+	for _, test := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if len(test.header) > 0 {
+			resp.Header.Set("Retry-After", test.header)
+		}
+
+		delay, ok := parseRetryAfter(resp, now)
+		if ok != test.ok || delay != test.expected {
+			t.Errorf("Expected Retry-After %q to parse to %v %t but got %v %t", test.header, test.expected, test.ok, delay, ok)
+		}
+	}
+
+	if _, ok := parseRetryAfter(nil, now); ok {
+		t.Error("Expected no Retry-After without a response")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{
+		MaxRetries: 5,
+		BaseDelay:  time.Second,
+		MaxDelay:   5 * time.Second,
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		backoff := min(time.Second<<attempt, policy.MaxDelay)
+
+		delay := policy.delay(attempt, nil)
+		if delay < backoff/2 || delay > backoff {
+			t.Errorf("Expected delay for attempt %d to be between %v and %v but was %v", attempt, backoff/2, backoff, delay)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "3")
+
+	if delay := policy.delay(0, resp); delay != 3*time.Second {
+		t.Errorf("Expected Retry-After to be honoured but delay was %v", delay)
+	}
+
+	resp.Header.Set("Retry-After", "60")
+
+	if delay := policy.delay(0, resp); delay != policy.MaxDelay {
+		t.Errorf("Expected Retry-After to be capped at %v but delay was %v", policy.MaxDelay, delay)
+	}
+
+	if delay := policy.delay(70, nil); delay < policy.MaxDelay/2 || delay > policy.MaxDelay {
+		t.Errorf("Expected an overflowing backoff to be capped at %v but delay was %v", policy.MaxDelay, delay)
+	}
+
+	noDelay := RetryPolicy{MaxRetries: 5, MaxDelay: 30 * time.Second}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		if delay := noDelay.delay(attempt, nil); delay != 0 {
+			t.Errorf("Expected no delay for attempt %d without a base delay but was %v", attempt, delay)
+		}
+	}
+}
@@ -1,12 +1,15 @@
 package paperapi
 
 import (
+	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/sprpgmr/papermc-fetch/files"
@@ -14,153 +17,398 @@ import (
 
 // Service contains methods to get paper api info conveniently.
 type Service interface {
-	GetLatestBuild(unstable bool, versionPrefix string) (*BuildInfo, error)
+	GetLatestBuild(ctx context.Context, project string, unstable bool, filter VersionFilter) (*BuildInfo, error)
+	GetBuild(ctx context.Context, project string, version string, build int) (*BuildInfo, error)
+	GetVersionsList(ctx context.Context, project string, filter VersionFilter) (*VersionsList, error)
+	GetBuilds(ctx context.Context, project string, version string) (*VersionBuilds, error)
+	GetChangelog(ctx context.Context, project string, fromVersion string, fromBuild int, toVersion string, toBuild int) ([]*BuildInfo, error)
 	IsValidDownload(filePath string, hash string) (bool, error)
-	DownloadJar(buildInfo *BuildInfo, filepath string) error
-	DownloadExists(filePath string, buildInfo *BuildInfo) (bool, error)
+	DownloadJar(ctx context.Context, buildInfo *BuildInfo, artifact string, filepath string) (string, error)
+	DownloadExists(filePath string, buildInfo *BuildInfo, artifact string) (bool, error)
+	DownloadURL(buildInfo *BuildInfo, artifact string) (string, error)
+	IdentifyJar(ctx context.Context, project string, filePath string, filter VersionFilter) (*BuildInfo, error)
 }
 
 type serviceImpl struct {
 	buildInfoService    BuildInfoService
 	versionsListService VersionsListService
 	buildsListService   BuildsListService
+	buildsService       BuildsService
 	fileService         files.Service
-	baseURL             string
+	client              *apiClient
 }
 
-func newServiceImpl(buildInfoService BuildInfoService, versionsListService VersionsListService, buildsListService BuildsListService, fileService files.Service, baseURL string) *serviceImpl {
+func newServiceImpl(buildInfoService BuildInfoService, versionsListService VersionsListService, buildsListService BuildsListService, buildsService BuildsService, fileService files.Service, client *apiClient) *serviceImpl {
 	return &serviceImpl{
 		buildInfoService:    buildInfoService,
 		versionsListService: versionsListService,
 		buildsListService:   buildsListService,
+		buildsService:       buildsService,
 		fileService:         fileService,
-		baseURL:             baseURL,
+		client:              client,
 	}
 }
 
-// GetLatestBuild will look for and return the BuildInfo of the latest stable version available, or latest unstable version available if unstable is true.
-func (s *serviceImpl) GetLatestBuild(unstable bool, versionPrefix string) (*BuildInfo, error) {
+// GetLatestBuild will look for and return the BuildInfo of the latest stable version of the project available, or latest unstable version available if unstable is true.
+// Only versions matching filter are considered.
+func (s *serviceImpl) GetLatestBuild(ctx context.Context, project string, unstable bool, filter VersionFilter) (*BuildInfo, error) {
 	if !unstable {
-		return s.getLatestStableVersion(versionPrefix)
+		return s.getLatestStableVersion(ctx, project, filter)
 	}
 
-	versions, err := s.getFilteredVersionsList(versionPrefix)
+	versions, err := s.getFilteredVersionsList(ctx, project, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(versions.Versions) == 0 {
-		return nil, errors.New("no versions found")
+		return nil, fmt.Errorf("%w: no versions of %s found", ErrVersionNotFound, project)
 	}
 
 	latestVersion := versions.Versions[len(versions.Versions)-1]
 
-	return s.getLatestBuildInfo(latestVersion)
+	return s.getLatestBuildInfo(ctx, project, latestVersion)
 }
 
-func (s *serviceImpl) getFilteredVersionsList(versionPrefix string) (*VersionsList, error) {
-	versions, err := s.versionsListService.GetVersionsList()
+// GetBuild will return the BuildInfo of exactly the version and build number of the project provided
+func (s *serviceImpl) GetBuild(ctx context.Context, project string, version string, build int) (*BuildInfo, error) {
+	return s.buildInfoService.GetBuildInfo(ctx, project, version, build)
+}
+
+// GetVersionsList will return the versions of the project that match filter, sorted oldest first
+func (s *serviceImpl) GetVersionsList(ctx context.Context, project string, filter VersionFilter) (*VersionsList, error) {
+	return s.getFilteredVersionsList(ctx, project, filter)
+}
+
+// GetBuilds will return the BuildInfo of every build of the project's version, sorted oldest first
+func (s *serviceImpl) GetBuilds(ctx context.Context, project string, version string) (*VersionBuilds, error) {
+	return s.buildsService.GetBuilds(ctx, project, version)
+}
+
+func (s *serviceImpl) getFilteredVersionsList(ctx context.Context, project string, filter VersionFilter) (*VersionsList, error) {
+	versions, err := s.versionsListService.GetVersionsList(ctx, project)
 	if err != nil {
 		return nil, err
 	}
 
-	return filterVersions(versionPrefix, versions), nil
+	return filterVersions(filter, versions), nil
 }
 
-func filterVersions(versionPrefix string, versions *VersionsList) *VersionsList {
-	if len(versionPrefix) == 0 {
-		return versions
+func (s *serviceImpl) getLatestStableVersion(ctx context.Context, project string, filter VersionFilter) (*BuildInfo, error) {
+	versions, err := s.getFilteredVersionsList(ctx, project, filter)
+	if err != nil {
+		return nil, err
 	}
 
-	filteredVersions := &VersionsList{}
-	filteredVersions.Versions = make([]string, 0)
+	// each version costs a single request, as the builds endpoint includes the channel of every build
+	for i := len(versions.Versions) - 1; i >= 0; i-- {
+		versionBuilds, err := s.buildsService.GetBuilds(ctx, project, versions.Versions[i])
+		if err != nil {
+			return nil, err
+		}
 
-	for i := 0; i < len(versions.Versions); i++ {
-		if strings.Index(versions.Versions[i], versionPrefix) == 0 {
-			if len(versions.Versions[i]) == len(versionPrefix) || versions.Versions[i][len(versionPrefix)] == '.' {
-				filteredVersions.Versions = append(filteredVersions.Versions, versions.Versions[i])
-			}
+		buildInfo := latestStableBuild(versionBuilds.Builds)
+		if buildInfo != nil {
+			return buildInfo, nil
 		}
 	}
 
-	return filteredVersions
+	return nil, fmt.Errorf("%w: no stable builds of %s found", ErrBuildNotFound, project)
 }
 
-func (s *serviceImpl) getLatestStableVersion(versionPrefix string) (*BuildInfo, error) {
-	versions, err := s.getFilteredVersionsList(versionPrefix)
-	if err != nil {
-		return nil, err
-	}
-
-	for i := len(versions.Versions) - 1; i >= 0; i-- {
-		buildInfo, err := s.getLatestBuildInfo(versions.Versions[i])
-		if buildInfo.Channel == "default" {
-			return buildInfo, err
+// latestStableBuild looks back through builds, sorted by build number, for the newest build on the default channel
+func latestStableBuild(builds []*BuildInfo) *BuildInfo {
+	for i := len(builds) - 1; i >= 0; i-- {
+		if builds[i].Channel == "default" {
+			return builds[i]
 		}
 	}
 
-	return nil, errors.New("no stable versions found")
+	return nil
 }
 
-func (s *serviceImpl) getLatestBuildInfo(version string) (*BuildInfo, error) {
-	builds, err := s.buildsListService.GetBuildsList(version)
+func (s *serviceImpl) getLatestBuildInfo(ctx context.Context, project string, version string) (*BuildInfo, error) {
+	builds, err := s.buildsListService.GetBuildsList(ctx, project, version)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(builds.Builds) == 0 {
-		return nil, errors.New("no builds for this version exist")
+		return nil, fmt.Errorf("%w: no builds of %s %s exist", ErrBuildNotFound, project, version)
 	}
 
 	latestBuild := builds.Builds[len(builds.Builds)-1]
 
-	buildInfo, err := s.buildInfoService.GetBuildInfo(version, latestBuild)
-	return buildInfo, nil
+	return s.buildInfoService.GetBuildInfo(ctx, project, version, latestBuild)
 }
 
 // IsValidDownload checks the sha256 sum of the filepath and compares it with the provided hash, returns true if they match
 func (s *serviceImpl) IsValidDownload(filePath string, hash string) (bool, error) {
+	output, err := fileSha256(filePath)
+	if err != nil {
+		return false, err
+	}
+
+	return output == hash, nil
+}
+
+// fileSha256 returns the hex encoded sha256 sum of the file at filePath
+func fileSha256(filePath string) (string, error) {
 	h := sha256.New()
 
 	file, err := os.Open(filePath)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
 	defer file.Close()
 
-	io.Copy(h, file)
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return "", err
+	}
 
-	output := fmt.Sprintf("%x", h.Sum(nil))
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
 
-	return output == hash, nil
+// DownloadJar will download the artifact, usually the application jar, of the specific project, version and build number provided, to filePath.
+// The jar is downloaded into a .part file next to filePath and only replaces filePath once its sha256 hash
+// has been verified, so an existing file at filePath is left untouched if anything goes wrong.
+// If a previous download of the same build was interrupted, the .part file is resumed where the server supports it.
+// The url the jar was downloaded from is returned, which is on a mirror if the first api url failed.
+func (s *serviceImpl) DownloadJar(ctx context.Context, info *BuildInfo, artifact string, filePath string) (string, error) {
+	if len(info.ProjectID) == 0 {
+		return "", errors.New("build info is missing the project to download from")
+	}
+
+	download, err := info.Downloads.Artifact(artifact)
+	if err != nil {
+		return "", err
+	}
+
+	downloadPath := downloadPath(info, download)
+	partPath := partFilePath(filePath, info)
+
+	// a download overtaken by a newer build will never be resumed, so don't leave it taking up space
+	removeStalePartFiles(filePath, partPath)
+
+	// the url of whichever api url served the download, so a mirror serving a corrupt file can be identified
+	var url string
+
+	// the .part file is kept when a download is interrupted, so each retry resumes from where the last one stopped
+	for attempt := 0; ; attempt++ {
+		url, err = s.downloadPart(ctx, downloadPath, partPath, download.Sha256)
+		if err == nil {
+			break
+		}
+
+		if attempt >= s.client.retryPolicy.MaxRetries || !isInterruptedDownload(ctx, err) {
+			removeEmptyFile(partPath)
+			return "", err
+		}
+
+		err = s.client.sleep(ctx, s.client.retryPolicy.delay(attempt, nil))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	actual, err := fileSha256(partPath)
+	if err != nil {
+		return "", err
+	}
+
+	if actual != download.Sha256 {
+		// a corrupt .part file can't be resumed, so start from scratch next time
+		os.Remove(partPath)
+
+		return "", &ChecksumError{
+			URL:      url,
+			Expected: download.Sha256,
+			Actual:   actual,
+		}
+	}
+
+	err = os.Rename(partPath, filePath)
+	if err != nil {
+		return "", err
+	}
+
+	return url, nil
 }
 
-// DownloadJar will download the paper jar file for the specific version and build number provided, to filepath
-func (s *serviceImpl) DownloadJar(info *BuildInfo, filepath string) error {
-	url := fmt.Sprint(s.baseURL, "/versions/", info.Version, "/builds/", info.Build, "/downloads/", info.Downloads.Application.Name)
-	resp, err := http.Get(url)
+// DownloadURL returns the url the artifact of the build would be downloaded from, using the first api url.
+// DownloadJar returns the url actually used, which may be a mirror.
+func (s *serviceImpl) DownloadURL(info *BuildInfo, artifact string) (string, error) {
+	download, err := info.Downloads.Artifact(artifact)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	defer resp.Body.Close()
+	return s.client.baseURLs[0] + downloadPath(info, download), nil
+}
+
+// downloadPath returns the path of the endpoint download is downloaded from, relative to the base url
+func downloadPath(info *BuildInfo, download *ArtifactInfo) string {
+	return fmt.Sprint(projectPath(info.ProjectID), "/versions/", info.Version, "/builds/", info.Build, "/downloads/", download.Name)
+}
 
-	file, err := os.OpenFile(filepath, os.O_CREATE|os.O_RDWR, 0644)
+// interruptedDownloadError is returned by downloadPart when reading the body of a response fails.
+// Failures getting a response are already retried by the client, so only these are retried by DownloadJar.
+type interruptedDownloadError struct {
+	err error
+}
+
+func (e *interruptedDownloadError) Error() string {
+	return fmt.Sprintf("download interrupted: %s", e.err)
+}
+
+func (e *interruptedDownloadError) Unwrap() error {
+	return e.err
+}
+
+// isInterruptedDownload returns true if err is the connection dropping part way through a download
+func isInterruptedDownload(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var interrupted *interruptedDownloadError
+
+	return errors.As(err, &interrupted)
+}
+
+// partFilePath returns the path of the file a build is downloaded into before it replaces filePath.
+// The build is part of the name so an interrupted download is never resumed with the bytes of another build.
+func partFilePath(filePath string, info *BuildInfo) string {
+	return fmt.Sprintf("%s.%s-%d.part", filePath, info.Version, info.Build)
+}
+
+// removeStalePartFiles removes the .part files of builds other than the one being downloaded into partPath
+func removeStalePartFiles(filePath string, partPath string) {
+	dir, name := filepath.Split(filePath)
+	if len(dir) == 0 {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), name+".") && strings.HasSuffix(entry.Name(), ".part") && path != filepath.Clean(partPath) {
+			os.Remove(path)
+		}
+	}
+}
+
+// removeEmptyFile removes the file at path if it has nothing in it, e.g. a .part file that no bytes were ever downloaded into
+func removeEmptyFile(path string) {
+	stat, err := os.Stat(path)
+	if err == nil && stat.Size() == 0 {
+		os.Remove(path)
+	}
+}
+
+// downloadPart downloads downloadPath into partPath, resuming from the end of partPath if it already exists and the server
+// supports range requests, otherwise starting again from the beginning. It returns the url the download was served from.
+// sha256 is the hash of the whole download, used to tell whether a .part file the server can't resume is already complete.
+func (s *serviceImpl) downloadPart(ctx context.Context, downloadPath string, partPath string, sha256 string) (string, error) {
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	defer file.Close()
 
+	stat, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	offset := stat.Size()
+
+	resp, err := s.client.getRange(ctx, downloadPath, offset)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// the server is resuming from offset
+	case http.StatusOK:
+		// the server ignored the range and is sending the whole file
+		offset = 0
+	case http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+
+		// the .part file may already hold the whole file, e.g. if it was never renamed over filePath
+		actual, err := fileSha256(partPath)
+		if err == nil && actual == sha256 {
+			return resp.Request.URL.String(), nil
+		}
+
+		// otherwise it isn't a prefix of the file on the server, so start again from the beginning
+		offset = 0
+
+		resp, err = s.client.getRange(ctx, downloadPath, 0)
+		if err != nil {
+			return "", err
+		}
+
+		defer resp.Body.Close()
+
+		err = checkStatus(resp, ErrBuildNotFound)
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", checkStatus(resp, ErrBuildNotFound)
+	}
+
+	err = file.Truncate(offset)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
 	_, err = io.Copy(file, resp.Body)
-	return err
+	if err != nil {
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			// writing to the .part file failed, e.g. the disk is full, which retrying won't fix
+			return "", err
+		}
+
+		return "", &interruptedDownloadError{err: err}
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Request.URL.String(), file.Close()
 }
 
-func (s *serviceImpl) DownloadExists(filepath string, buildInfo *BuildInfo) (bool, error) {
+// DownloadExists checks if filepath already contains the artifact of the build provided
+func (s *serviceImpl) DownloadExists(filepath string, buildInfo *BuildInfo, artifact string) (bool, error) {
+	download, err := buildInfo.Downloads.Artifact(artifact)
+	if err != nil {
+		return false, err
+	}
 
 	if s.fileService.FileExists(filepath) {
-		valid, err := s.IsValidDownload(filepath, buildInfo.Downloads.Application.Sha256)
+		valid, err := s.IsValidDownload(filepath, download.Sha256)
 		if err != nil {
 			return false, err
 		}
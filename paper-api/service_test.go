@@ -1,12 +1,17 @@
 package paperapi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestFilterVersions(t *testing.T) {
@@ -14,7 +19,7 @@ func TestFilterVersions(t *testing.T) {
 		Versions: []string{"1.20", "1.2", "1.23.2", "1.19.0", "1.2.4"},
 	}
 
-	output := filterVersions("1.2", input)
+	output := filterVersions(VersionFilter{Prefix: "1.2"}, input)
 
 	failed := false
 
@@ -44,7 +49,7 @@ func TestIsValidDownload(t *testing.T) {
 
 	defer cleanupTestFile(fileName)
 
-	valid, err := newServiceImpl(nil, nil, nil, nil, "").IsValidDownload(fileName, expected)
+	valid, err := newServiceImpl(nil, nil, nil, nil, nil, nil).IsValidDownload(fileName, expected)
 	if err != nil {
 		t.Error(err)
 	}
@@ -85,7 +90,7 @@ type buildsListServiceMock struct {
 	getBuildsListHandler func(s buildsListServiceMock, version string) (*BuildsList, error)
 }
 
-func (s buildsListServiceMock) GetBuildsList(version string) (*BuildsList, error) {
+func (s buildsListServiceMock) GetBuildsList(ctx context.Context, project string, version string) (*BuildsList, error) {
 	return s.getBuildsListHandler(s, version)
 }
 
@@ -93,15 +98,23 @@ type buildInfoServiceMock struct {
 	getBuildInfoHandler func(s buildInfoServiceMock, version string, build int) (*BuildInfo, error)
 }
 
-func (s buildInfoServiceMock) GetBuildInfo(version string, build int) (*BuildInfo, error) {
+func (s buildInfoServiceMock) GetBuildInfo(ctx context.Context, project string, version string, build int) (*BuildInfo, error) {
 	return s.getBuildInfoHandler(s, version, build)
 }
 
+type buildsServiceMock struct {
+	getBuildsHandler func(s buildsServiceMock, version string) (*VersionBuilds, error)
+}
+
+func (s buildsServiceMock) GetBuilds(ctx context.Context, project string, version string) (*VersionBuilds, error) {
+	return s.getBuildsHandler(s, version)
+}
+
 type versionsListServiceMock struct {
 	getVersionsListHandler func(s versionsListServiceMock) (*VersionsList, error)
 }
 
-func (s versionsListServiceMock) GetVersionsList() (*VersionsList, error) {
+func (s versionsListServiceMock) GetVersionsList(ctx context.Context, project string) (*VersionsList, error) {
 	return s.getVersionsListHandler(s)
 }
 
@@ -125,13 +138,13 @@ func TestGetLatestBuildInfo(t *testing.T) {
 
 	buildInfoMock.getBuildInfoHandler = func(s buildInfoServiceMock, version string, build int) (*BuildInfo, error) {
 		if version == "1.20.2" && build == 9 {
-			applicationInfo := &ApplicationInfo{
+			applicationInfo := &ArtifactInfo{
 				Name:   "asdf",
 				Sha256: "1234",
 			}
 
-			downloadInfo := &DownloadInfo{
-				Application: applicationInfo,
+			downloadInfo := DownloadInfo{
+				ApplicationArtifact: applicationInfo,
 			}
 
 			buildInfo := &BuildInfo{
@@ -152,9 +165,9 @@ func TestGetLatestBuildInfo(t *testing.T) {
 		return &VersionsList{Versions: []string{}}, nil
 	}
 
-	service := newServiceImpl(buildInfoMock, nil, buildsListMock, nil, "")
+	service := newServiceImpl(buildInfoMock, nil, buildsListMock, nil, nil, nil)
 
-	buildInfo, err := service.getLatestBuildInfo("1.20.2")
+	buildInfo, err := service.getLatestBuildInfo(context.Background(), "paper", "1.20.2")
 	if err != nil {
 		t.Error(err)
 	}
@@ -181,9 +194,13 @@ func TestGetLatestBuild(t *testing.T) {
 		getVersionsListHandler: handleGetVersionsForTestGetLatestBuild,
 	}
 
-	service := newServiceImpl(buildsInfoMock, versionsListMock, buildsListMock, nil, "")
+	buildsMock := buildsServiceMock{
+		getBuildsHandler: handleGetBuildsForTestGetLatestBuild,
+	}
 
-	buildInfo, err := service.GetLatestBuild(false, "")
+	service := newServiceImpl(buildsInfoMock, versionsListMock, buildsListMock, buildsMock, nil, nil)
+
+	buildInfo, err := service.GetLatestBuild(context.Background(), "paper", false, VersionFilter{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -197,7 +214,7 @@ func TestGetLatestBuild(t *testing.T) {
 		t.Errorf("Expected build number to be 3, but it was %d", buildInfo.Build)
 	}
 
-	buildInfo, err = service.GetLatestBuild(true, "")
+	buildInfo, err = service.GetLatestBuild(context.Background(), "paper", true, VersionFilter{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -211,7 +228,7 @@ func TestGetLatestBuild(t *testing.T) {
 		t.Errorf("Expected build number to be 3, but it was %d", buildInfo.Build)
 	}
 
-	buildInfo, err = service.GetLatestBuild(true, "1.19")
+	buildInfo, err = service.GetLatestBuild(context.Background(), "paper", true, VersionFilter{Prefix: "1.19"})
 	if err != nil {
 		t.Error(err)
 	}
@@ -255,6 +272,19 @@ func handleGetBuildInfoForTestGetLatestBuild(s buildInfoServiceMock, version str
 	}, nil
 }
 
+func handleGetBuildsForTestGetLatestBuild(s buildsServiceMock, version string) (*VersionBuilds, error) {
+	versionBuilds := &VersionBuilds{
+		Version: version,
+	}
+
+	for build := 1; build <= 3; build++ {
+		buildInfo, _ := handleGetBuildInfoForTestGetLatestBuild(buildInfoServiceMock{}, version, build)
+		versionBuilds.Builds = append(versionBuilds.Builds, buildInfo)
+	}
+
+	return versionBuilds, nil
+}
+
 func handleGetVersionsForTestGetLatestBuild(s versionsListServiceMock) (*VersionsList, error) {
 	versionsList := &VersionsList{
 		Versions: []string{
@@ -267,6 +297,128 @@ func handleGetVersionsForTestGetLatestBuild(s versionsListServiceMock) (*Version
 	return versionsList, nil
 }
 
+func TestGetLatestStableBuildMakesOneRequestPerVersion(t *testing.T) {
+	requestedVersions := []string{}
+
+	buildsMock := buildsServiceMock{}
+	buildsMock.getBuildsHandler = func(s buildsServiceMock, version string) (*VersionBuilds, error) {
+		requestedVersions = append(requestedVersions, version)
+
+		return handleGetBuildsForTestGetLatestBuild(s, version)
+	}
+
+	buildsListMock := buildsListServiceMock{}
+	buildsListMock.getBuildsListHandler = func(s buildsListServiceMock, version string) (*BuildsList, error) {
+		t.Errorf("Didn't expect the builds list to be requested for %s", version)
+
+		return nil, nil
+	}
+
+	buildInfoMock := buildInfoServiceMock{}
+	buildInfoMock.getBuildInfoHandler = func(s buildInfoServiceMock, version string, build int) (*BuildInfo, error) {
+		t.Errorf("Didn't expect build info to be requested for %s build %d", version, build)
+
+		return nil, nil
+	}
+
+	versionsListMock := versionsListServiceMock{
+		getVersionsListHandler: handleGetVersionsForTestGetLatestBuild,
+	}
+
+	service := newServiceImpl(buildInfoMock, versionsListMock, buildsListMock, buildsMock, nil, nil)
+
+	buildInfo, err := service.GetLatestBuild(context.Background(), "paper", false, VersionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buildInfo.Version != "1.20.1" {
+		t.Errorf("Expected latest stable version to be 1.20.1 but it was %s", buildInfo.Version)
+	}
+
+	expectedVersions := []string{"1.20.3", "1.20.1"}
+	if !slices.Equal[[]string](requestedVersions, expectedVersions) {
+		t.Errorf("Expected builds to be requested for %v but they were requested for %v", expectedVersions, requestedVersions)
+	}
+}
+
+func TestLatestStableBuild(t *testing.T) {
+	builds := []*BuildInfo{
+		{Build: 1, Channel: "experimental"},
+		{Build: 2, Channel: "default"},
+		{Build: 3, Channel: "default"},
+		{Build: 4, Channel: "experimental"},
+	}
+
+	buildInfo := latestStableBuild(builds)
+	if buildInfo == nil || buildInfo.Build != 3 {
+		t.Errorf("Expected build 3 to be the latest stable build but got %+v", buildInfo)
+	}
+
+	buildInfo = latestStableBuild(builds[:1])
+	if buildInfo != nil {
+		t.Errorf("Expected no stable build but got %+v", buildInfo)
+	}
+
+	buildInfo = latestStableBuild(nil)
+	if buildInfo != nil {
+		t.Errorf("Expected no stable build but got %+v", buildInfo)
+	}
+}
+
+func TestGetLatestStableBuildWithMixedChannels(t *testing.T) {
+	versionsListMock := versionsListServiceMock{
+		getVersionsListHandler: handleGetVersionsForTestGetLatestBuild,
+	}
+
+	// 1.20.3 has only experimental builds, 1.20.1 has an experimental build after its last stable build
+	buildsMock := buildsServiceMock{}
+	buildsMock.getBuildsHandler = func(s buildsServiceMock, version string) (*VersionBuilds, error) {
+		versionBuilds := &VersionBuilds{Version: version}
+
+		switch version {
+		case "1.20.3":
+			versionBuilds.Builds = []*BuildInfo{
+				{Version: version, Build: 1, Channel: "experimental"},
+				{Version: version, Build: 2, Channel: "experimental"},
+			}
+		case "1.20.1":
+			versionBuilds.Builds = []*BuildInfo{
+				{Version: version, Build: 10, Channel: "experimental"},
+				{Version: version, Build: 11, Channel: "default"},
+				{Version: version, Build: 12, Channel: "default"},
+				{Version: version, Build: 13, Channel: "experimental"},
+			}
+		default:
+			versionBuilds.Builds = []*BuildInfo{
+				{Version: version, Build: 1, Channel: "default"},
+			}
+		}
+
+		return versionBuilds, nil
+	}
+
+	service := newServiceImpl(nil, versionsListMock, nil, buildsMock, nil, nil)
+
+	buildInfo, err := service.GetLatestBuild(context.Background(), "paper", false, VersionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buildInfo.Version != "1.20.1" {
+		t.Errorf("Expected latest stable version to be 1.20.1 but it was %s", buildInfo.Version)
+	}
+
+	if buildInfo.Build != 12 {
+		t.Errorf("Expected latest stable build to be 12 but it was %d", buildInfo.Build)
+	}
+
+	buildInfo, err = service.GetLatestBuild(context.Background(), "paper", false, VersionFilter{Prefix: "1.20.3"})
+	if err == nil {
+		t.Errorf("Expected an error as 1.20.3 has no stable builds, but got %+v", buildInfo)
+	}
+}
+
 func TestDownloadFile(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		data := "asdf\n"
@@ -274,22 +426,23 @@ func TestDownloadFile(t *testing.T) {
 		fmt.Fprint(w, data)
 	}))
 
-	service := newServiceImpl(nil, nil, nil, nil, ts.URL)
+	service := newServiceImpl(nil, nil, nil, nil, nil, newAPIClient([]string{ts.URL}, RetryPolicy{}))
 
 	buildInfo := &BuildInfo{
-		Version: "1.2.3",
-		Build:   123,
-		Downloads: &DownloadInfo{
-			Application: &ApplicationInfo{
+		ProjectID: "paper",
+		Version:   "1.2.3",
+		Build:     123,
+		Downloads: DownloadInfo{
+			ApplicationArtifact: {
 				Name:   "paper.jar",
-				Sha256: "asdf",
+				Sha256: "d1bc8d3ba4afc7e109612cb73acbdddac052c93025aa1f82942edabb7deb82a1",
 			},
 		},
 	}
 
 	filename := ".testfile"
 
-	err := service.DownloadJar(buildInfo, filename)
+	_, err := service.DownloadJar(context.Background(), buildInfo, ApplicationArtifact, filename)
 	if err != nil {
 		t.Error(err)
 	}
@@ -307,3 +460,419 @@ func TestDownloadFile(t *testing.T) {
 		t.Error("Download isn't valid")
 	}
 }
+
+func TestDownloadFileCancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "asdf")
+		w.(http.Flusher).Flush()
+
+		// hang until the client gives up
+		<-r.Context().Done()
+	}))
+
+	defer ts.Close()
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, newAPIClient([]string{ts.URL}, RetryPolicy{}))
+
+	buildInfo := &BuildInfo{
+		ProjectID: "paper",
+		Version:   "1.2.3",
+		Build:     123,
+		Downloads: DownloadInfo{
+			ApplicationArtifact: {
+				Name:   "paper.jar",
+				Sha256: "asdf",
+			},
+		},
+	}
+
+	filename := filepath.Join(t.TempDir(), "paper.jar")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := service.DownloadJar(ctx, buildInfo, ApplicationArtifact, filename)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected download to fail with a deadline exceeded error but got %v", err)
+	}
+
+	if _, err := os.Stat(filename); err == nil {
+		t.Error("Expected the partial download not to replace the file")
+	}
+
+	contents, err := os.ReadFile(partFilePath(filename, buildInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(contents) != "asdf" {
+		t.Errorf("Expected the partial download to be kept to resume from, but it contained %q", contents)
+	}
+}
+
+func TestDownloadFileResumesPartFile(t *testing.T) {
+	requestedRange := ""
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedRange = r.Header.Get("Range")
+		http.ServeContent(w, r, "paper.jar", time.Time{}, strings.NewReader("asdf\n"))
+	}))
+
+	defer ts.Close()
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, newAPIClient([]string{ts.URL}, RetryPolicy{}))
+	buildInfo := testDownloadBuildInfo()
+
+	filename := filepath.Join(t.TempDir(), "paper.jar")
+
+	err := os.WriteFile(partFilePath(filename, buildInfo), []byte("as"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.DownloadJar(context.Background(), buildInfo, ApplicationArtifact, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requestedRange != "bytes=2-" {
+		t.Errorf("Expected download to resume with range bytes=2- but requested %q", requestedRange)
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(contents) != "asdf\n" {
+		t.Errorf("Expected resumed download to contain the whole file but it contained %q", contents)
+	}
+
+	if _, err := os.Stat(partFilePath(filename, buildInfo)); err == nil {
+		t.Error("Expected the part file to be renamed over the file")
+	}
+}
+
+func TestDownloadFileRestartsWithoutRangeSupport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "asdf\n")
+	}))
+
+	defer ts.Close()
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, newAPIClient([]string{ts.URL}, RetryPolicy{}))
+	buildInfo := testDownloadBuildInfo()
+
+	filename := filepath.Join(t.TempDir(), "paper.jar")
+
+	err := os.WriteFile(partFilePath(filename, buildInfo), []byte("xxxxxxxxxxxxxxxx"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.DownloadJar(context.Background(), buildInfo, ApplicationArtifact, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(contents) != "asdf\n" {
+		t.Errorf("Expected the download to restart from the beginning but it contained %q", contents)
+	}
+}
+
+func TestDownloadFileRemovesStalePartFiles(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "asdf\n")
+	}))
+
+	defer ts.Close()
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, newAPIClient([]string{ts.URL}, RetryPolicy{}))
+	buildInfo := testDownloadBuildInfo()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "paper.jar")
+	stale := partFilePath(filename, &BuildInfo{Version: "1.2.3", Build: 122})
+	other := filepath.Join(dir, "velocity.jar.1.2.3-122.part")
+
+	for _, path := range []string{stale, other} {
+		err := os.WriteFile(path, []byte("as"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := service.DownloadJar(context.Background(), buildInfo, ApplicationArtifact, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(stale); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the part file of an older build to be removed but got %v", err)
+	}
+
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Expected the part files of other files to be kept but got %v", err)
+	}
+}
+
+func TestDownloadFileNotFoundRemovesEmptyPartFile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	defer ts.Close()
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, newAPIClient([]string{ts.URL}, RetryPolicy{}))
+	buildInfo := testDownloadBuildInfo()
+
+	dir := t.TempDir()
+
+	_, err := service.DownloadJar(context.Background(), buildInfo, ApplicationArtifact, filepath.Join(dir, "paper.jar"))
+	if !errors.Is(err, ErrBuildNotFound) {
+		t.Errorf("Expected ErrBuildNotFound but got %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 0 {
+		t.Errorf("Expected no part file to be left behind but found %d files", len(entries))
+	}
+}
+
+func TestDownloadFileCompletePartFile(t *testing.T) {
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.ServeContent(w, r, "paper.jar", time.Time{}, strings.NewReader("asdf\n"))
+	}))
+
+	defer ts.Close()
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, newAPIClient([]string{ts.URL}, RetryPolicy{}))
+	buildInfo := testDownloadBuildInfo()
+
+	filename := filepath.Join(t.TempDir(), "paper.jar")
+
+	err := os.WriteFile(partFilePath(filename, buildInfo), []byte("asdf\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.DownloadJar(context.Background(), buildInfo, ApplicationArtifact, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected a complete part file not to be downloaded again but there were %d requests", requests)
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil || string(contents) != "asdf\n" {
+		t.Errorf("Expected the complete part file to replace the file but it contained %q %v", contents, err)
+	}
+}
+
+func testDownloadBuildInfo() *BuildInfo {
+	return &BuildInfo{
+		ProjectID: "paper",
+		Version:   "1.2.3",
+		Build:     123,
+		Downloads: DownloadInfo{
+			ApplicationArtifact: {
+				Name:   "paper.jar",
+				Sha256: "d1bc8d3ba4afc7e109612cb73acbdddac052c93025aa1f82942edabb7deb82a1",
+			},
+		},
+	}
+}
+
+func TestDownloadFileChecksumMismatchKeepsExistingFile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "corrupt\n")
+	}))
+
+	defer ts.Close()
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, newAPIClient([]string{ts.URL}, RetryPolicy{}))
+
+	buildInfo := &BuildInfo{
+		ProjectID: "paper",
+		Version:   "1.2.3",
+		Build:     123,
+		Downloads: DownloadInfo{
+			ApplicationArtifact: {
+				Name:   "paper.jar",
+				Sha256: "d1bc8d3ba4afc7e109612cb73acbdddac052c93025aa1f82942edabb7deb82a1",
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "paper.jar")
+
+	err := os.WriteFile(filename, []byte("working jar"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.DownloadJar(context.Background(), buildInfo, ApplicationArtifact, filename)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected a checksum mismatch error but got %v", err)
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(contents) != "working jar" {
+		t.Errorf("Expected existing file to be untouched but it contained %q", contents)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("Expected the temporary download to be cleaned up but found %d files", len(entries))
+	}
+}
+
+func TestDownloadFileRetriesInterruptedDownload(t *testing.T) {
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if requests == 1 {
+			// promise the whole file, but drop the connection after the first two bytes
+			w.Header().Set("Content-Length", "5")
+			fmt.Fprint(w, "as")
+			w.(http.Flusher).Flush()
+
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+
+			return
+		}
+
+		http.ServeContent(w, r, "paper.jar", time.Time{}, strings.NewReader("asdf\n"))
+	}))
+
+	defer ts.Close()
+
+	delays := []time.Duration{}
+	client := newTestClient(ts.URL, RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Second}, &delays)
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, client)
+	buildInfo := testDownloadBuildInfo()
+
+	filename := filepath.Join(t.TempDir(), "paper.jar")
+
+	_, err := service.DownloadJar(context.Background(), buildInfo, ApplicationArtifact, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected the download to be retried once but there were %d requests", requests)
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(contents) != "asdf\n" {
+		t.Errorf("Expected the retried download to contain the whole file but it contained %q", contents)
+	}
+}
+
+func TestDownloadFileConnectionRefusedRetriedOnce(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	delays := []time.Duration{}
+	client := newTestClient(url, RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second}, &delays)
+
+	attempts := 0
+	transport := &http.Transport{}
+	client.httpClient = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return transport.RoundTrip(req)
+	})}
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, client)
+
+	_, err := service.DownloadJar(context.Background(), testDownloadBuildInfo(), ApplicationArtifact, filepath.Join(t.TempDir(), "paper.jar"))
+	if err == nil {
+		t.Fatal("Expected an error connecting to a closed server")
+	}
+
+	if attempts != 4 || len(delays) != 3 {
+		t.Errorf("Expected --retries 3 to make 4 attempts waiting 3 times but made %d attempts waiting %d times", attempts, len(delays))
+	}
+}
+
+func TestDownloadFileReturnsMirrorURL(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "asdf\n")
+	}))
+	defer mirror.Close()
+
+	service := newServiceImpl(nil, nil, nil, nil, nil, newAPIClient([]string{failing.URL, mirror.URL}, RetryPolicy{}))
+
+	url, err := service.DownloadJar(context.Background(), testDownloadBuildInfo(), ApplicationArtifact, filepath.Join(t.TempDir(), "paper.jar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := mirror.URL + "/projects/paper/versions/1.2.3/builds/123/downloads/paper.jar"
+	if url != expected {
+		t.Errorf("Expected the download to be from the mirror %s but got %s", expected, url)
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGetBuild(t *testing.T) {
+	buildInfoMock := buildInfoServiceMock{}
+	buildInfoMock.getBuildInfoHandler = func(s buildInfoServiceMock, version string, build int) (*BuildInfo, error) {
+		return &BuildInfo{Version: version, Build: build, Channel: "default"}, nil
+	}
+
+	service := newServiceImpl(buildInfoMock, nil, nil, nil, nil, nil)
+
+	buildInfo, err := service.GetBuild(context.Background(), "paper", "1.20.4", 430)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buildInfo.Version != "1.20.4" || buildInfo.Build != 430 {
+		t.Errorf("Expected 1.20.4 build 430 but got %s build %d", buildInfo.Version, buildInfo.Build)
+	}
+}
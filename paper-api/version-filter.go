@@ -0,0 +1,61 @@
+package paperapi
+
+import "strings"
+
+// VersionFilter selects which of a project's versions are considered
+type VersionFilter struct {
+	// Prefix only matches versions in this release line, e.g. 1.20 matches 1.20, 1.20.4 and 1.20.5-pre1 but not 1.2
+	Prefix string
+
+	// ExcludePreReleases leaves out snapshots, pre-releases and release candidates of minecraft.
+	// Velocity's -SNAPSHOT versions are its own development builds and are kept.
+	ExcludePreReleases bool
+
+	// Constraint only matches versions selected by the constraint, if it isn't nil
+	Constraint *Constraint
+}
+
+// matches returns true if version is selected by the filter
+func (f VersionFilter) matches(version string) bool {
+	if f.Constraint != nil && !f.Constraint.Matches(version) {
+		return false
+	}
+
+	parsed, err := ParseMinecraftVersion(version)
+	if err != nil {
+		// versions that can't be parsed can only be matched by their text
+		return len(f.Prefix) == 0 || version == f.Prefix || strings.HasPrefix(version, f.Prefix+".")
+	}
+
+	if f.ExcludePreReleases && parsed.IsPreRelease() {
+		return false
+	}
+
+	if len(f.Prefix) == 0 {
+		return true
+	}
+
+	prefix, err := ParseMinecraftVersion(f.Prefix)
+	if err != nil {
+		return false
+	}
+
+	return parsed.HasPrefix(prefix)
+}
+
+func filterVersions(filter VersionFilter, versions *VersionsList) *VersionsList {
+	if filter == (VersionFilter{}) {
+		return versions
+	}
+
+	filteredVersions := &VersionsList{ProjectID: versions.ProjectID}
+	filteredVersions.Versions = make([]string, 0)
+
+	for _, version := range versions.Versions {
+		if filter.matches(version) {
+			filteredVersions.Versions = append(filteredVersions.Versions, version)
+		}
+	}
+
+	return filteredVersions
+}
@@ -0,0 +1,55 @@
+package paperapi
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilterPreReleases(t *testing.T) {
+	input := &VersionsList{
+		Versions: []string{"1.20.4", "1.20.5-pre1", "1.20.5-rc1", "1.20.5", "1.21-pre1", "24w14a"},
+	}
+
+	tests := []struct {
+		filter   VersionFilter
+		expected []string
+	}{
+		{VersionFilter{}, input.Versions},
+		{VersionFilter{ExcludePreReleases: true}, []string{"1.20.4", "1.20.5"}},
+		{VersionFilter{Prefix: "1.20.5"}, []string{"1.20.5-pre1", "1.20.5-rc1", "1.20.5"}},
+		{VersionFilter{Prefix: "1.20", ExcludePreReleases: true}, []string{"1.20.4", "1.20.5"}},
+		{VersionFilter{Prefix: "1.21"}, []string{"1.21-pre1"}},
+		{VersionFilter{Prefix: "1.21-pre1"}, []string{"1.21-pre1"}},
+	}
+
+	for _, test := range tests {
+		output := filterVersions(test.filter, input)
+
+		if !slices.Equal(output.Versions, test.expected) {
+			t.Errorf("Expected %+v to keep %v but got %v", test.filter, test.expected, output.Versions)
+		}
+	}
+}
+
+func TestFilterVelocityVersions(t *testing.T) {
+	input := &VersionsList{
+		Versions: []string{"3.1.1-SNAPSHOT", "3.2.0-SNAPSHOT", "3.3.0-SNAPSHOT"},
+	}
+
+	tests := []struct {
+		filter   VersionFilter
+		expected []string
+	}{
+		{VersionFilter{ExcludePreReleases: true}, input.Versions},
+		{VersionFilter{Prefix: "3.3"}, []string{"3.3.0-SNAPSHOT"}},
+		{VersionFilter{Prefix: "3", ExcludePreReleases: true}, input.Versions},
+	}
+
+	for _, test := range tests {
+		output := filterVersions(test.filter, input)
+
+		if !slices.Equal(output.Versions, test.expected) {
+			t.Errorf("Expected %+v to keep %v but got %v", test.filter, test.expected, output.Versions)
+		}
+	}
+}
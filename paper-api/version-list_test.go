@@ -1,6 +1,7 @@
 package paperapi
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -20,6 +21,18 @@ func TestSortVersions(t *testing.T) {
 	}
 }
 
+func TestSortPreReleases(t *testing.T) {
+	input := []string{"1.21", "1.21-rc1", "1.20.6", "1.21-pre2", "1.21-pre1", "1.21.1"}
+
+	sortVersions(input)
+
+	expected := []string{"1.20.6", "1.21-pre1", "1.21-pre2", "1.21-rc1", "1.21", "1.21.1"}
+
+	if !slices.Equal(input, expected) {
+		t.Errorf("sort didn't sort correctly. Expected %v, got %v", expected, input)
+	}
+}
+
 func TestCompareVersions(t *testing.T) {
 	if compareVersions("1.2", "1.23") != -1 {
 		t.Errorf("Expected 1.2 to be less than 1.23")
@@ -45,9 +58,9 @@ func TestGetVersions(t *testing.T) {
 
 	defer ts.Close()
 
-	versionListService := newVersionsListServiceImpl(ts.URL)
+	versionListService := newVersionsListServiceImpl(newAPIClient([]string{ts.URL}, RetryPolicy{}))
 
-	versionList, err := versionListService.GetVersionsList()
+	versionList, err := versionListService.GetVersionsList(context.Background(), "paper")
 	if err != nil {
 		t.Error(err)
 	}
@@ -58,3 +71,34 @@ func TestGetVersions(t *testing.T) {
 		t.Errorf("Expected versionList %v to match %v", versionList.Versions, expectedVersions)
 	}
 }
+
+func TestGetVersionsUsesProject(t *testing.T) {
+	requestedPath := ""
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		fmt.Fprint(w, "{ \"project_id\": \"velocity\", \"versions\": [ \"3.3.0-SNAPSHOT\", \"3.2.0-SNAPSHOT\" ] }")
+	}))
+
+	defer ts.Close()
+
+	versionListService := newVersionsListServiceImpl(newAPIClient([]string{ts.URL}, RetryPolicy{}))
+
+	versionList, err := versionListService.GetVersionsList(context.Background(), "velocity")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if requestedPath != "/projects/velocity" {
+		t.Errorf("Expected request path to be /projects/velocity but was %s", requestedPath)
+	}
+
+	if versionList.ProjectID != "velocity" {
+		t.Errorf("Expected versionList.ProjectID '%s' to equal velocity", versionList.ProjectID)
+	}
+
+	_, err = versionListService.GetVersionsList(context.Background(), "")
+	if err == nil {
+		t.Error("Expected an error when no project is specified")
+	}
+}
@@ -1,90 +1,83 @@
 package paperapi
 
 import (
+	"context"
 	"encoding/json"
-	"net/http"
+	"errors"
 	"slices"
-	"strconv"
 	"strings"
 )
 
 // VersionsList contains a list of available paper versions
 type VersionsList struct {
-	Versions []string `json:"versions"`
+	ProjectID string   `json:"project_id"`
+	Versions  []string `json:"versions"`
 }
 
 // VersionsListService provides methods for getting a list of versions
 type VersionsListService interface {
-	GetVersionsList() (*VersionsList, error)
+	GetVersionsList(ctx context.Context, project string) (*VersionsList, error)
 }
 
 type versionsListServiceImpl struct {
-	baseURL string
+	client *apiClient
 }
 
-func newVersionsListServiceImpl(baseURL string) *versionsListServiceImpl {
+func newVersionsListServiceImpl(client *apiClient) *versionsListServiceImpl {
 	return &versionsListServiceImpl{
-		baseURL: baseURL,
+		client: client,
 	}
 }
 
-// GetVersionsList will query the paper website to get a list of versions available
-func (v *versionsListServiceImpl) GetVersionsList() (*VersionsList, error) {
-	resp, err := http.Get(v.baseURL)
+// GetVersionsList will query the paper website to get a list of versions available for the project
+func (v *versionsListServiceImpl) GetVersionsList(ctx context.Context, project string) (*VersionsList, error) {
+	if len(project) == 0 {
+		return nil, errors.New("must specify project to get versions for")
+	}
+
+	resp, err := v.client.get(ctx, projectPath(project))
 	if err != nil {
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
+	err = checkStatus(resp, ErrProjectNotFound)
+	if err != nil {
+		return nil, err
+	}
+
 	dec := *json.NewDecoder(resp.Body)
 
 	versionList := &VersionsList{}
 	err = dec.Decode(versionList)
+	if err != nil {
+		return nil, err
+	}
 
 	sortVersions(versionList.Versions)
 
-	return versionList, err
+	return versionList, nil
 }
 
 func sortVersions(versions []string) {
 	slices.SortFunc[[]string](versions, compareVersions)
 }
 
+// compareVersions orders versions using their parsed MinecraftVersion. Versions that can't be parsed are older than any
+// that can, and ordered by their text.
 func compareVersions(a, b string) int {
-	aParts := strings.Split(a, ".")
-	bParts := strings.Split(b, ".")
-
-	for i := 0; i < max(len(aParts), len(bParts)); i++ {
-		aPiece := "0"
-		bPiece := "0"
-
-		if len(aParts) >= i+1 {
-			aPiece = aParts[i]
-		}
-
-		if len(bParts) >= i+1 {
-			bPiece = bParts[i]
-		}
-
-		aInt, err := strconv.Atoi(aPiece)
-		if err != nil {
-			aInt = 0
-		}
-
-		bInt, err := strconv.Atoi(bPiece)
-		if err != nil {
-			bInt = 0
-		}
-
-		if aInt > bInt {
-			return 1
-		}
-
-		if aInt < bInt {
-			return -1
-		}
+	aVersion, aErr := ParseMinecraftVersion(a)
+	bVersion, bErr := ParseMinecraftVersion(b)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		return aVersion.Compare(bVersion)
+	case aErr == nil:
+		return 1
+	case bErr == nil:
+		return -1
 	}
 
-	return 0
+	return strings.Compare(a, b)
 }
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sprpgmr/papermc-fetch/files"
+)
+
+type rollbackCommand struct {
+	To string `long:"to" description:"version and build to restore, e.g. 1.20.4-430, defaults to the jar replaced by the last update" value-name:"VERSION-BUILD"`
+}
+
+// archiveDir returns the directory jars replaced by updates are kept in
+func archiveDir(opts *programArgs) string {
+	if len(opts.ArchiveDir) > 0 {
+		return opts.ArchiveDir
+	}
+
+	return opts.Filename + ".archive"
+}
+
+// archiveName returns the name a build is kept under in the archive
+func archiveName(version string, build int) string {
+	return fmt.Sprintf("%s-%d.jar", version, build)
+}
+
+// archiveInstalled copies the jar about to be replaced into the archive, and prunes the archive down to --keep jars.
+// The jar is named by its version and build, or by when it was last modified if they can't be detected.
+func archiveInstalled(archiveService files.ArchiveService, opts *programArgs, version string, build int, log io.Writer) error {
+	stat, err := os.Stat(opts.Filename)
+	if errors.Is(err, os.ErrNotExist) {
+		// nothing is being replaced
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("unknown-%s.jar", stat.ModTime().UTC().Format("20060102-150405"))
+	if len(version) > 0 {
+		name = archiveName(version, build)
+	}
+
+	dir := archiveDir(opts)
+
+	err = archiveService.Add(dir, opts.Filename, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(log, "Archived %s as %s.\n", opts.Filename, strings.TrimSuffix(name, ".jar"))
+
+	pruned, err := archiveService.Prune(dir, opts.Keep)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range pruned {
+		fmt.Fprintf(log, "Removed %s from the archive.\n", file.Name)
+	}
+
+	return nil
+}
+
+// runRollback restores the jar asked for by --to, or the jar replaced by the last update, from the archive
+func runRollback(archiveService files.ArchiveService, opts *programArgs, out io.Writer) error {
+	dir := archiveDir(opts)
+
+	name := opts.Rollback.To
+	if len(name) > 0 && !strings.HasSuffix(name, ".jar") {
+		name += ".jar"
+	}
+
+	if len(name) == 0 {
+		// each update archives the jar it replaces, so the most recently archived jar is the one before the current one
+		archived, err := archiveService.List(dir)
+		if err != nil {
+			return err
+		}
+
+		if len(archived) == 0 {
+			return fmt.Errorf("%w: no previous jar in %s to roll back to", files.ErrNotArchived, dir)
+		}
+
+		name = archived[0].Name
+	}
+
+	err := archiveService.Restore(dir, name, opts.Filename)
+	if errors.Is(err, files.ErrNotArchived) {
+		return fmt.Errorf("%w: %s isn't in %s", err, name, dir)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the lock file describes the jar that was just replaced
+	err = os.Remove(lockFilePath(opts.Filename))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	fmt.Fprintf(out, "Rolled back %s to %s.\n", opts.Filename, strings.TrimSuffix(name, ".jar"))
+
+	return nil
+}
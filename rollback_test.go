@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sprpgmr/papermc-fetch/files"
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+func setupArchive(t *testing.T, archiveService files.ArchiveService, jar string, archiveDir string, names ...string) {
+	for i, name := range names {
+		err := os.WriteFile(jar, []byte(name), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = archiveService.Add(archiveDir, jar, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		archivedAt := time.Now().Add(time.Duration(i-len(names)) * time.Minute)
+		os.Chtimes(filepath.Join(archiveDir, name), archivedAt, archivedAt)
+	}
+}
+
+func TestRollbackToPrevious(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	writeServerJar(t, jar, "1.20.2-318-abcdef0")
+
+	previous, err := os.ReadFile(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serviceMock := newLatestBuildServiceMock()
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filePath string) error {
+		return os.WriteFile(filePath, []byte("1.20.4-461"), 0644)
+	}
+
+	archiveService := files.GetArchiveService()
+
+	// the jar installed before anything was archived is archived when the first update replaces it
+	_, err = runMainProgram(mockServiceFactory(serviceMock), archiveService, []string{"--file", jar})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archived, err := archiveService.List(archiveDir(&programArgs{Filename: jar}))
+	if err != nil || len(archived) != 1 || archived[0].Name != "1.20.2-318.jar" {
+		t.Fatalf("Expected only the replaced jar to be archived but got %v %v", archived, err)
+	}
+
+	exitCode, err := runMainProgram(mockServiceFactory(serviceMock), archiveService, []string{"--file", jar, "rollback"})
+	if err != nil || exitCode != exitUpdated {
+		t.Fatalf("Expected the rollback to succeed but got %d %v", exitCode, err)
+	}
+
+	contents, err := os.ReadFile(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(contents, previous) {
+		t.Errorf("Expected the jar replaced by the update to be restored but got %q", contents)
+	}
+}
+
+func TestRollbackTo(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	opts := &programArgs{Filename: jar, ArchiveDir: filepath.Join(filepath.Dir(jar), "archive")}
+	opts.Rollback.To = "1.20.2-318"
+
+	archiveService := files.GetArchiveService()
+	setupArchive(t, archiveService, jar, opts.ArchiveDir, "1.20.2-318.jar", "1.20.4-430.jar", "1.20.4-461.jar")
+
+	err := runRollback(archiveService, opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := os.ReadFile(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(contents) != "1.20.2-318.jar" {
+		t.Errorf("Expected 1.20.2-318 to be restored but got %q", contents)
+	}
+
+	opts.Rollback.To = "1.19.4-1"
+
+	err = runRollback(archiveService, opts, io.Discard)
+	if !errors.Is(err, files.ErrNotArchived) {
+		t.Errorf("Expected ErrNotArchived rolling back to a build that isn't archived but got %v", err)
+	}
+
+	if exitCodeFor(err) != exitNotFound {
+		t.Errorf("Expected exit code %d but got %d", exitNotFound, exitCodeFor(err))
+	}
+}
+
+func TestRollbackWithoutPrevious(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "paper.jar")
+	opts := &programArgs{Filename: jar}
+
+	err := runRollback(files.GetArchiveService(), opts, io.Discard)
+	if !errors.Is(err, files.ErrNotArchived) {
+		t.Errorf("Expected ErrNotArchived without a previous jar but got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/sprpgmr/papermc-fetch/files"
+	"github.com/sprpgmr/papermc-fetch/httpclient"
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+	"github.com/sprpgmr/papermc-fetch/webhook"
+)
+
+type watchCommand struct {
+	Interval time.Duration `long:"interval" description:"how often to check for new builds" value-name:"DURATION" default:"10m"`
+	Jitter   time.Duration `long:"jitter" description:"up to how long to randomly add to each interval, so many servers don't check at once" value-name:"DURATION" default:"1m"`
+}
+
+// maxWatchBackoff is the longest the watcher waits between checks after repeated failures, unless the interval is longer
+const maxWatchBackoff = time.Hour
+
+// watchTarget is a file the watcher keeps up to date, along with the last build it was updated to
+type watchTarget struct {
+	name string
+	opts *programArgs
+	last *paperapi.BuildInfo
+}
+
+// watchTargets returns the targets listed in --config, or a single target for the command line flags,
+// along with a notifier for their webhooks
+func watchTargets(opts *programArgs) ([]*watchTarget, webhook.Notifier, error) {
+	if len(opts.Config) == 0 {
+		notifier, err := newNotifier(opts, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		targetOpts := *opts
+		return []*watchTarget{{opts: &targetOpts}}, notifier, nil
+	}
+
+	cfg, err := loadConfig(opts.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notifier, err := newNotifier(opts, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targets := make([]*watchTarget, 0, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		targets = append(targets, &watchTarget{name: target.Name, opts: target.apply(opts)})
+	}
+
+	return targets, notifier, nil
+}
+
+// runWatch checks for new builds of every target each interval until ctx is done, updating a target only when a build
+// appears that it hasn't already been updated to. --timeout applies to each check rather than the whole run.
+// In json mode a result is printed to out, one per line, whenever a target is updated or fails to.
+func runWatch(ctx context.Context, paperAPIService paperapi.Service, archiveService files.ArchiveService, opts *programArgs, out io.Writer, log io.Writer) error {
+	if opts.Watch.Interval <= 0 {
+		return fmt.Errorf("%w: --interval must be more than 0", errUsage)
+	}
+
+	targets, notifier, err := watchTargets(opts)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	failures := 0
+
+	for {
+		failed := false
+
+		for _, target := range targets {
+			result, err := pollTarget(ctx, paperAPIService, archiveService, target, log)
+			if ctx.Err() != nil {
+				fmt.Fprintln(log, "Stopped watching for new builds.")
+				return nil
+			}
+
+			if err != nil {
+				fmt.Fprintf(log, "Couldn't update %s: %s\n", result.File, err)
+				failed = true
+			}
+
+			if result == nil {
+				continue
+			}
+
+			notify(ctx, notifier, result, log)
+
+			if opts.Output == "json" {
+				enc.Encode(result)
+			}
+		}
+
+		if failed {
+			failures++
+		} else {
+			failures = 0
+		}
+
+		err = httpclient.Sleep(ctx, pollDelay(opts.Watch.Interval, opts.Watch.Jitter, failures))
+		if err != nil {
+			fmt.Fprintln(log, "Stopped watching for new builds.")
+			return nil
+		}
+	}
+}
+
+// pollTarget looks for the latest or pinned build of target, and updates it if it's a build the target hasn't been
+// updated to yet. The result is nil if there was nothing to do.
+func pollTarget(ctx context.Context, paperAPIService paperapi.Service, archiveService files.ArchiveService, target *watchTarget, log io.Writer) (*updateResult, error) {
+	if target.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, target.opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	buildInfo, err := resolveBuild(ctx, paperAPIService, target.opts, log)
+	if err == nil && buildInfo != nil && target.last != nil && sameBuild(buildInfo, target.last) {
+		fmt.Fprintln(log, "No new builds since the last check.")
+		return nil, nil
+	}
+
+	result := &updateResult{Project: target.opts.Project, File: target.opts.Filename}
+	if err == nil {
+		result, err = updateBuild(ctx, paperAPIService, archiveService, target.opts, buildInfo, log)
+	}
+
+	result.Target = target.name
+	result.finish(start, err)
+
+	// failed builds are tried again at the next check
+	if err == nil {
+		target.last = buildInfo
+	}
+
+	return result, err
+}
+
+// sameBuild returns true if a and b are the same build of the same project
+func sameBuild(a *paperapi.BuildInfo, b *paperapi.BuildInfo) bool {
+	return a.ProjectID == b.ProjectID && a.Version == b.Version && a.Build == b.Build
+}
+
+// pollDelay returns how long to wait before the next check, a random amount up to jitter on top of interval.
+// After consecutive failures the interval doubles for each one, up to maxWatchBackoff.
+func pollDelay(interval time.Duration, jitter time.Duration, failures int) time.Duration {
+	delay := interval
+
+	for i := 0; i < failures && delay < maxWatchBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxWatchBackoff && interval < maxWatchBackoff {
+		delay = maxWatchBackoff
+	}
+
+	if jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(jitter)))
+	}
+
+	return delay
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	paperapi "github.com/sprpgmr/papermc-fetch/paper-api"
+)
+
+// newWatchServiceMock returns a mock whose latest build is the next of builds on each check, cancelling ctx once they run out
+func newWatchServiceMock(cancel context.CancelFunc, builds ...int) *paperServiceMock {
+	serviceMock := newLatestBuildServiceMock()
+	latestBuild := serviceMock.getLatestBuildHandler
+
+	checks := 0
+	serviceMock.getLatestBuildHandler = func(s *paperServiceMock, ctx context.Context, project string, unstable bool, filter paperapi.VersionFilter) (*paperapi.BuildInfo, error) {
+		if checks == len(builds) {
+			cancel()
+			return nil, ctx.Err()
+		}
+
+		buildInfo, err := latestBuild(s, ctx, project, unstable, filter)
+		buildInfo.Build = builds[checks]
+		checks++
+
+		return buildInfo, err
+	}
+
+	return serviceMock
+}
+
+func newWatchOpts() *programArgs {
+	opts := &programArgs{Project: "paper", Filename: "paper.jar", Artifact: paperapi.ApplicationArtifact}
+	opts.Watch.Interval = time.Millisecond
+
+	return opts
+}
+
+func TestWatchOnlyUpdatesNewBuilds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serviceMock := newWatchServiceMock(cancel, 461, 461, 462, 462)
+
+	downloaded := []int{}
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filePath string) error {
+		downloaded = append(downloaded, buildInfo.Build)
+		return nil
+	}
+
+	err := runWatch(ctx, serviceMock, &archiveServiceMock{}, newWatchOpts(), io.Discard, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(downloaded) != 2 || downloaded[0] != 461 || downloaded[1] != 462 {
+		t.Errorf("Expected each new build to be downloaded once but got %v", downloaded)
+	}
+}
+
+func TestWatchRetriesFailedUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serviceMock := newWatchServiceMock(cancel, 461, 461)
+
+	attempts := 0
+	serviceMock.downloadJarHandler = func(s *paperServiceMock, ctx context.Context, buildInfo *paperapi.BuildInfo, artifact string, filePath string) error {
+		attempts++
+		if attempts == 1 {
+			return paperapi.ErrChecksumMismatch
+		}
+
+		return nil
+	}
+
+	opts := newWatchOpts()
+	opts.Watch.Interval = time.Nanosecond
+
+	err := runWatch(ctx, serviceMock, &archiveServiceMock{}, opts, io.Discard, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected the failed build to be tried again at the next check but it was tried %d times", attempts)
+	}
+}
+
+func TestWatchRequiresInterval(t *testing.T) {
+	opts := newWatchOpts()
+	opts.Watch.Interval = 0
+
+	err := runWatch(context.Background(), &paperServiceMock{}, &archiveServiceMock{}, opts, io.Discard, io.Discard)
+	if !errors.Is(err, errUsage) {
+		t.Errorf("Expected a usage error but got %v", err)
+	}
+}
+
+func TestPollDelay(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		failures int
+		expected time.Duration
+	}{
+		{10 * time.Minute, 0, 10 * time.Minute},
+		{10 * time.Minute, 2, 40 * time.Minute},
+		{10 * time.Minute, 10, maxWatchBackoff},
+		{2 * time.Hour, 3, 2 * time.Hour},
+	}
+
+	for _, test := range tests {
+		actual := pollDelay(test.interval, 0, test.failures)
+		if actual != test.expected {
+			t.Errorf("Expected %s after %d failures with a %s interval but got %s", test.expected, test.failures, test.interval, actual)
+		}
+	}
+
+	jittered := pollDelay(time.Minute, time.Second, 0)
+	if jittered < time.Minute || jittered >= time.Minute+time.Second {
+		t.Errorf("Expected up to a second of jitter but got %s", jittered)
+	}
+}
@@ -0,0 +1,245 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sprpgmr/papermc-fetch/httpclient"
+)
+
+// Types of event that can be sent to webhooks
+const (
+	EventUpdated            = "updated"
+	EventAvailable          = "available"
+	EventFailed             = "failed"
+	EventVerificationFailed = "verification-failed"
+)
+
+// Event describes something that happened to a target, and is the data webhook templates are executed with
+type Event struct {
+	Type       string    `json:"event"`
+	Target     string    `json:"target,omitempty"`
+	Project    string    `json:"project"`
+	File       string    `json:"file"`
+	OldVersion string    `json:"old_version,omitempty"`
+	OldBuild   int       `json:"old_build,omitempty"`
+	NewVersion string    `json:"new_version,omitempty"`
+	NewBuild   int       `json:"new_build,omitempty"`
+	Channel    string    `json:"channel,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	HookError  string    `json:"hook_error,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// Hook is a url events are POSTed to
+type Hook struct {
+	URL string `yaml:"url"`
+
+	// Template is a text/template executed with the Event to produce the json payload, the Event itself is sent if empty
+	Template string `yaml:"template"`
+
+	// Events limits which types of event are sent, every type is sent if empty
+	Events []string `yaml:"events"`
+}
+
+// RetryPolicy controls how many times and how far apart failed deliveries are retried
+type RetryPolicy struct {
+	MaxRetries int
+	Delay      time.Duration
+}
+
+// Notifier sends events to webhooks
+type Notifier interface {
+	Notify(ctx context.Context, event *Event) error
+}
+
+type notifierImpl struct {
+	hooks       []*compiledHook
+	retryPolicy RetryPolicy
+	httpClient  *http.Client
+}
+
+type compiledHook struct {
+	Hook
+	template *template.Template
+}
+
+// GetNotifier returns a notifier for hooks that sends events with httpClient, or http.DefaultClient if it's nil.
+// It returns an error if any of the hooks' templates or events are invalid.
+func GetNotifier(hooks []Hook, retryPolicy RetryPolicy, httpClient *http.Client) (Notifier, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return newNotifierImpl(hooks, retryPolicy, httpClient)
+}
+
+func newNotifierImpl(hooks []Hook, retryPolicy RetryPolicy, httpClient *http.Client) (*notifierImpl, error) {
+	n := &notifierImpl{
+		retryPolicy: retryPolicy,
+		httpClient:  httpClient,
+	}
+
+	for _, hook := range hooks {
+		if len(hook.URL) == 0 {
+			return nil, errors.New("webhook has no url")
+		}
+
+		for _, event := range hook.Events {
+			if !slices.Contains([]string{EventUpdated, EventAvailable, EventFailed, EventVerificationFailed}, event) {
+				return nil, fmt.Errorf("webhook %s has unknown event %s", hook.URL, event)
+			}
+		}
+
+		compiled := &compiledHook{Hook: hook}
+
+		if len(hook.Template) > 0 {
+			tmpl, err := template.New(hook.URL).Funcs(template.FuncMap{"json": toJSON}).Parse(hook.Template)
+			if err != nil {
+				return nil, fmt.Errorf("webhook %s has an invalid template: %w", hook.URL, err)
+			}
+
+			compiled.template = tmpl
+		}
+
+		n.hooks = append(n.hooks, compiled)
+	}
+
+	return n, nil
+}
+
+// toJSON lets templates quote and escape values, e.g. {"content": {{json .Error}}}
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// Notify POSTs event to every hook that wants its type, trying every hook even if some fail
+func (n *notifierImpl) Notify(ctx context.Context, event *Event) error {
+	var errs []error
+
+	for _, hook := range n.hooks {
+		if len(hook.Events) > 0 && !slices.Contains(hook.Events, event.Type) {
+			continue
+		}
+
+		err := n.send(ctx, hook, event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", hook.URL, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// send renders the payload for event and POSTs it to hook, retrying connection errors, 5xx and 429 responses
+func (n *notifierImpl) send(ctx context.Context, hook *compiledHook, event *Event) error {
+	payload, err := hook.payload(event)
+	if err != nil {
+		return err
+	}
+
+	delay := n.retryPolicy.Delay
+
+	for attempt := 0; ; attempt++ {
+		err = n.post(ctx, hook.URL, payload)
+		if err == nil || attempt >= n.retryPolicy.MaxRetries || !isRetryable(ctx, err) {
+			return err
+		}
+
+		err = httpclient.Sleep(ctx, delay)
+		if err != nil {
+			return err
+		}
+
+		delay *= 2
+	}
+}
+
+// payload returns the json body to send to the hook for event
+func (h *compiledHook) payload(event *Event) ([]byte, error) {
+	if h.template == nil {
+		return json.Marshal(event)
+	}
+
+	buf := &bytes.Buffer{}
+
+	err := h.template.Execute(buf, event)
+	if err != nil {
+		return nil, err
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("template produced invalid json: %s", strings.TrimSpace(buf.String()))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// statusError is returned when a webhook responds with a status other than 2xx
+type statusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *statusError) Error() string {
+	return "unexpected response " + e.Status
+}
+
+func (n *notifierImpl) post(ctx context.Context, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &statusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	return nil
+}
+
+// isRetryable returns true if err is a network error or timeout, or a 5xx or 429 response, and ctx isn't done.
+// Errors such as malformed urls and untrusted certificates won't go away by trying again, so aren't retried.
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+
+	// every error from the http client is a *url.Error, which is itself a net.Error, so look at what it wraps
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,240 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testEvent() *Event {
+	return &Event{
+		Type:       EventUpdated,
+		Project:    "paper",
+		File:       "paper.jar",
+		OldVersion: "1.20.4",
+		OldBuild:   430,
+		NewVersion: "1.20.4",
+		NewBuild:   461,
+		Channel:    "default",
+		Time:       time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNotifySendsEvent(t *testing.T) {
+	var received Event
+	contentType := ""
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+
+		err := json.NewDecoder(r.Body).Decode(&received)
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+
+	defer ts.Close()
+
+	notifier, err := newNotifierImpl([]Hook{{URL: ts.URL}}, RetryPolicy{}, ts.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = notifier.Notify(context.Background(), testEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if contentType != "application/json" {
+		t.Errorf("Expected a json content type but got %s", contentType)
+	}
+
+	if received != *testEvent() {
+		t.Errorf("Expected %+v but received %+v", testEvent(), received)
+	}
+}
+
+func TestNotifyRendersTemplate(t *testing.T) {
+	body := ""
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+
+	defer ts.Close()
+
+	hook := Hook{
+		URL:      ts.URL,
+		Template: `{"content": {{json (printf "%s updated from %s #%d to %s #%d" .File .OldVersion .OldBuild .NewVersion .NewBuild)}}}`,
+	}
+
+	notifier, err := newNotifierImpl([]Hook{hook}, RetryPolicy{}, ts.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = notifier.Notify(context.Background(), testEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"content": "paper.jar updated from 1.20.4 #430 to 1.20.4 #461"}`
+	if body != expected {
+		t.Errorf("Expected %s but received %s", expected, body)
+	}
+}
+
+func TestNotifyRejectsInvalidJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected nothing to be sent")
+	}))
+
+	defer ts.Close()
+
+	notifier, err := newNotifierImpl([]Hook{{URL: ts.URL, Template: `{"content": {{.File}}}`}}, RetryPolicy{}, ts.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = notifier.Notify(context.Background(), testEvent())
+	if err == nil {
+		t.Error("Expected an error for a template that isn't json")
+	}
+}
+
+func TestNotifyFiltersEvents(t *testing.T) {
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+
+	defer ts.Close()
+
+	notifier, err := newNotifierImpl([]Hook{{URL: ts.URL, Events: []string{EventFailed, EventVerificationFailed}}}, RetryPolicy{}, ts.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notifier.Notify(context.Background(), testEvent())
+
+	failed := testEvent()
+	failed.Type = EventVerificationFailed
+	notifier.Notify(context.Background(), failed)
+
+	if requests != 1 {
+		t.Errorf("Expected only the failure to be sent but %d requests were made", requests)
+	}
+}
+
+func TestNotifyRetriesFailures(t *testing.T) {
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+
+	defer ts.Close()
+
+	notifier, err := newNotifierImpl([]Hook{{URL: ts.URL}}, RetryPolicy{MaxRetries: 3, Delay: time.Millisecond}, ts.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = notifier.Notify(context.Background(), testEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 3 {
+		t.Errorf("Expected 3 requests but there were %d", requests)
+	}
+}
+
+func TestNotifyDoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	defer ts.Close()
+
+	notifier, err := newNotifierImpl([]Hook{{URL: ts.URL}}, RetryPolicy{MaxRetries: 3, Delay: time.Millisecond}, ts.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = notifier.Notify(context.Background(), testEvent())
+	if err == nil || requests != 1 {
+		t.Errorf("Expected a single failed request but got %d requests and %v", requests, err)
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNotifyRetriesOnlyTransientErrors(t *testing.T) {
+	untrusted := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer untrusted.Close()
+
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	tests := []struct {
+		name     string
+		url      string
+		attempts int
+	}{
+		{"connection refused", closed.URL, 3},
+		{"untrusted certificate", untrusted.URL, 1},
+		{"unsupported scheme", "ftp://example.com/hook", 1},
+	}
+
+	for _, test := range tests {
+		attempts := 0
+		transport := &http.Transport{}
+		client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return transport.RoundTrip(req)
+		})}
+
+		notifier, err := newNotifierImpl([]Hook{{URL: test.url}}, RetryPolicy{MaxRetries: 2, Delay: time.Millisecond}, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = notifier.Notify(context.Background(), testEvent())
+		if err == nil || attempts != test.attempts {
+			t.Errorf("%s: expected %d attempts but got %d and %v", test.name, test.attempts, attempts, err)
+		}
+	}
+}
+
+func TestInvalidHooks(t *testing.T) {
+	hooks := map[string]Hook{
+		"missing url":   {},
+		"bad template":  {URL: "http://localhost", Template: "{{.File"},
+		"unknown event": {URL: "http://localhost", Events: []string{"installed"}},
+	}
+
+	for name, hook := range hooks {
+		_, err := GetNotifier([]Hook{hook}, RetryPolicy{}, nil)
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}